@@ -22,6 +22,7 @@ import (
 
 	"github.com/attestantio/go-eth2-client/spec/bellatrix"
 	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -331,3 +332,55 @@ func ExecutionPayloadV2ToBlock(payload *capella.ExecutionPayload) (*types.Block,
 	block := types.NewBlockWithHeader(header).WithBody(txs, nil /* uncles */).WithWithdrawals(withdrawals)
 	return block, nil
 }
+
+// BlockToExecutionPayloadV2 encodes a post-Shanghai block as a Capella execution payload, it
+// reverses ExecutionPayloadV2ToBlock.
+func BlockToExecutionPayloadV2(block *types.Block) (*capella.ExecutionPayload, error) {
+	transactions := make([]bellatrix.Transaction, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		enc, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode transaction %d: %v", i, err)
+		}
+		transactions[i] = enc
+	}
+
+	withdrawals := make([]*capella.Withdrawal, len(block.Withdrawals()))
+	for i, withdrawal := range block.Withdrawals() {
+		withdrawals[i] = &capella.Withdrawal{
+			Index:          capella.WithdrawalIndex(withdrawal.Index),
+			ValidatorIndex: phase0.ValidatorIndex(withdrawal.Validator),
+			Address:        bellatrix.ExecutionAddress(withdrawal.Address),
+			Amount:         phase0.Gwei(withdrawal.Amount),
+		}
+	}
+
+	// base fee per gas is stored little-endian.
+	if block.BaseFee() == nil || block.BaseFee().BitLen() > 256 {
+		return nil, fmt.Errorf("invalid base fee %v", block.BaseFee())
+	}
+	var baseFeePerGasBytes [32]byte
+	block.BaseFee().FillBytes(baseFeePerGasBytes[:])
+	var baseFeePerGas [32]byte
+	for i := 0; i < 32; i++ {
+		baseFeePerGas[i] = baseFeePerGasBytes[32-1-i]
+	}
+
+	return &capella.ExecutionPayload{
+		ParentHash:    phase0.Hash32(block.ParentHash()),
+		FeeRecipient:  bellatrix.ExecutionAddress(block.Coinbase()),
+		StateRoot:     block.Root(),
+		ReceiptsRoot:  block.ReceiptHash(),
+		LogsBloom:     block.Bloom(),
+		PrevRandao:    block.MixDigest(),
+		BlockNumber:   block.NumberU64(),
+		GasLimit:      block.GasLimit(),
+		GasUsed:       block.GasUsed(),
+		Timestamp:     block.Time(),
+		ExtraData:     block.Extra(),
+		BaseFeePerGas: baseFeePerGas,
+		BlockHash:     phase0.Hash32(block.Hash()),
+		Transactions:  transactions,
+		Withdrawals:   withdrawals,
+	}, nil
+}
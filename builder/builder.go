@@ -260,7 +260,7 @@ func (b *Builder) submitBellatrixBlock(block *types.Block, blockValue *big.Int,
 	}
 
 	if b.dryRun {
-		err = b.validator.ValidateBuilderSubmissionV1(&blockvalidation.BuilderBlockValidationRequest{SubmitBlockRequest: blockSubmitReq, RegisteredGasLimit: vd.GasLimit})
+		err = b.validator.ValidateBuilderSubmissionV1(context.Background(), &blockvalidation.BuilderBlockValidationRequest{SubmitBlockRequest: blockSubmitReq, RegisteredGasLimit: vd.GasLimit})
 		if err != nil {
 			log.Error("could not validate bellatrix block", "err", err)
 		}
@@ -319,7 +319,7 @@ func (b *Builder) submitCapellaBlock(block *types.Block, blockValue *big.Int, or
 	}
 
 	if b.dryRun {
-		err = b.validator.ValidateBuilderSubmissionV2(&blockvalidation.BuilderBlockValidationRequestV2{SubmitBlockRequest: blockSubmitReq, RegisteredGasLimit: vd.GasLimit})
+		err = b.validator.ValidateBuilderSubmissionV2(context.Background(), &blockvalidation.BuilderBlockValidationRequestV2{SubmitBlockRequest: blockSubmitReq, RegisteredGasLimit: vd.GasLimit})
 		if err != nil {
 			log.Error("could not validate block for capella", "err", err)
 		}
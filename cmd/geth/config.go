@@ -30,6 +30,8 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/usbwallet"
 	"github.com/ethereum/go-ethereum/builder"
 	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	blockvalidationapi "github.com/ethereum/go-ethereum/eth/block-validation"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
@@ -173,10 +175,41 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 	// Configure log filter RPC API.
 	filterSystem := utils.RegisterFilterAPI(stack, backend, &cfg.Eth)
 
-	bvConfig := blockvalidationapi.BlockValidationConfig{}
+	bvConfig := blockvalidationapi.BlockValidationConfig{
+		AllowIndirectPayment: ctx.Bool(utils.BuilderBlockValidationAllowIndirectPayment.Name),
+	}
+	if ctx.IsSet(utils.BuilderBlockValidationBlacklistSourceFilePath.Name) {
+		bvConfig.BlacklistSourceFilePath = ctx.String(utils.BuilderBlockValidationBlacklistSourceFilePath.Name)
+	}
 	if ctx.IsSet(utils.BuilderBlockValidationUseBalanceDiff.Name) {
 		bvConfig.UseBalanceDiffProfit = ctx.Bool(utils.BuilderBlockValidationUseBalanceDiff.Name)
 	}
+	if ctx.IsSet(utils.BuilderBlockValidationProfitMode.Name) {
+		bvConfig.ProfitMode = blockvalidationapi.ProfitMode(ctx.String(utils.BuilderBlockValidationProfitMode.Name))
+	}
+	if ctx.Bool(utils.BuilderBlockValidationVerifyBuilderSignature.Name) {
+		genesisForkVersion, err := hexutil.Decode(cfg.Builder.GenesisForkVersion)
+		if err != nil || len(genesisForkVersion) != len(bvConfig.GenesisForkVersion) {
+			utils.Fatalf("Invalid genesis fork version %q: %v", cfg.Builder.GenesisForkVersion, err)
+		}
+		bvConfig.VerifyBuilderSignature = true
+		copy(bvConfig.GenesisForkVersion[:], genesisForkVersion)
+	}
+	if ctx.IsSet(utils.BuilderBlockValidationProfitMultiplier.Name) {
+		bvConfig.ProfitMultiplier = ctx.Float64(utils.BuilderBlockValidationProfitMultiplier.Name)
+	}
+	if ctx.IsSet(utils.BuilderBlockValidationAuditLog.Name) {
+		bvConfig.AuditLogPath = ctx.String(utils.BuilderBlockValidationAuditLog.Name)
+	}
+	if ctx.IsSet(utils.BuilderBlockValidationExpectedGenesisHash.Name) {
+		bvConfig.ExpectedGenesisHash = common.HexToHash(ctx.String(utils.BuilderBlockValidationExpectedGenesisHash.Name))
+	}
+	if ctx.IsSet(utils.BuilderBlockValidationMaxSubmissionsPerSlot.Name) {
+		bvConfig.MaxSubmissionsPerSlot = ctx.Int(utils.BuilderBlockValidationMaxSubmissionsPerSlot.Name)
+	}
+	if ctx.IsSet(utils.BuilderBlockValidationOTLPEndpoint.Name) {
+		bvConfig.OTLPEndpoint = ctx.String(utils.BuilderBlockValidationOTLPEndpoint.Name)
+	}
 
 	if err := blockvalidationapi.Register(stack, eth, bvConfig); err != nil {
 		utils.Fatalf("Failed to register the Block Validation API: %v", err)
@@ -161,7 +161,16 @@ var (
 		utils.BuilderAlgoTypeFlag,
 		utils.BuilderPriceCutoffPercentFlag,
 		utils.BuilderEnableValidatorChecks,
+		utils.BuilderBlockValidationBlacklistSourceFilePath,
 		utils.BuilderBlockValidationUseBalanceDiff,
+		utils.BuilderBlockValidationProfitMode,
+		utils.BuilderBlockValidationVerifyBuilderSignature,
+		utils.BuilderBlockValidationProfitMultiplier,
+		utils.BuilderBlockValidationAllowIndirectPayment,
+		utils.BuilderBlockValidationAuditLog,
+		utils.BuilderBlockValidationExpectedGenesisHash,
+		utils.BuilderBlockValidationMaxSubmissionsPerSlot,
+		utils.BuilderBlockValidationOTLPEndpoint,
 		utils.BuilderEnableLocalRelay,
 		utils.BuilderSecondsInSlot,
 		utils.BuilderSlotsInEpoch,
@@ -741,6 +741,50 @@ var (
 		Value:    false,
 		Category: flags.BuilderCategory,
 	}
+	BuilderBlockValidationVerifyBuilderSignature = &cli.BoolFlag{
+		Name:     "builder.validation_verify_builder_signature",
+		Usage:    "Block validation API will verify the builder signature of the bid, in the domain of builder.genesis_fork_version.",
+		Value:    false,
+		Category: flags.BuilderCategory,
+	}
+	BuilderBlockValidationProfitMode = &cli.StringFlag{
+		Name:     "builder.validation_profit_mode",
+		Usage:    "Block validation API will verify the proposer payment of V2 blocks with this mode (balanceDiff, feesOnly or coinbaseTransfer), overriding builder.validation_use_balance_diff",
+		Category: flags.BuilderCategory,
+	}
+	BuilderBlockValidationProfitMultiplier = &cli.Float64Flag{
+		Name:     "builder.validation_profit_multiplier",
+		Usage:    "Block validation API will report base fee * gas target * multiplier as the expected block value. Zero disables the policy.",
+		Value:    0,
+		Category: flags.BuilderCategory,
+	}
+	BuilderBlockValidationAllowIndirectPayment = &cli.BoolFlag{
+		Name:     "builder.validation_allow_indirect_payment",
+		Usage:    "Block validation API will accept blocks that pay the proposer with a transaction instead of setting it as the coinbase.",
+		Value:    true,
+		Category: flags.BuilderCategory,
+	}
+	BuilderBlockValidationAuditLog = &cli.StringFlag{
+		Name:     "builder.validation_audit_log",
+		Usage:    "Path of the file every V1 and V2 validation attempt is appended to as NDJSON, rotated daily at midnight UTC",
+		Category: flags.BuilderCategory,
+	}
+	BuilderBlockValidationExpectedGenesisHash = &cli.StringFlag{
+		Name:     "builder.validation_expected_genesis_hash",
+		Usage:    "Genesis block hash the block validation API requires the local chain to start from",
+		Category: flags.BuilderCategory,
+	}
+	BuilderBlockValidationMaxSubmissionsPerSlot = &cli.IntFlag{
+		Name:     "builder.validation_max_submissions_per_slot",
+		Usage:    "Block validation API will delay submissions beyond this number per slot with exponential backoff. Zero disables throttling.",
+		Value:    0,
+		Category: flags.BuilderCategory,
+	}
+	BuilderBlockValidationOTLPEndpoint = &cli.StringFlag{
+		Name:     "builder.validation_otlp_endpoint",
+		Usage:    "OTLP/HTTP endpoint the block validation API pushes its metrics to every 30 seconds",
+		Category: flags.BuilderCategory,
+	}
 	BuilderEnableLocalRelay = &cli.BoolFlag{
 		Name:     "builder.local_relay",
 		Usage:    "Enable the local relay",
@@ -120,3 +120,12 @@ func (v *BlockValidator) ValidateState(block *types.Block, statedb *state.StateD
 func CalcGasLimit(parentGasLimit, desiredLimit uint64) uint64 {
 	return utils.CalcGasLimit(parentGasLimit, desiredLimit)
 }
+
+// VerifyRegisteredGasLimit checks that the gas limit of a block moves from the gas limit of its
+// parent towards the gas limit registered by the proposer by as much as the protocol allows.
+func VerifyRegisteredGasLimit(gasLimit, parentGasLimit, registeredGasLimit uint64) error {
+	if expected := CalcGasLimit(parentGasLimit, registeredGasLimit); expected != gasLimit {
+		return fmt.Errorf("incorrect gas limit set, expected: %d, header: %d", expected, gasLimit)
+	}
+	return nil
+}
@@ -389,3 +389,24 @@ func TestCalcGasLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestVerifyRegisteredGasLimit(t *testing.T) {
+	for i, tc := range []struct {
+		gasLimit   uint64
+		parent     uint64
+		registered uint64
+		valid      bool
+	}{
+		{30000000, 30000000, 30000000, true},
+		{30000001, 30000000, 30000000, false},
+		{29999999, 30000000, 30000000, false},
+		{20019530, 20000000, 30000000, true},
+		{20019529, 20000000, 30000000, false},
+		{29970705, 30000000, 20000000, true},
+		{30000000, 30000000, 20000000, false},
+	} {
+		if err := VerifyRegisteredGasLimit(tc.gasLimit, tc.parent, tc.registered); (err == nil) != tc.valid {
+			t.Errorf("test %d: have err %v, want valid %v", i, err, tc.valid)
+		}
+	}
+}
@@ -18,6 +18,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -38,7 +39,6 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/core/utils"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
@@ -2498,31 +2498,53 @@ func (bc *BlockChain) SetBlockValidatorAndProcessorForTesting(v Validator, p Pro
 // It returns nil if the payload is valid, otherwise it returns an error.
 //   - `useBalanceDiffProfit` if set to false, proposer payment is assumed to be in the last transaction of the block
 //     otherwise we use proposer balance changes after the block to calculate proposer payment (see details in the code)
-func (bc *BlockChain) ValidatePayload(block *types.Block, feeRecipient common.Address, expectedProfit *big.Int, registeredGasLimit uint64, vmConfig vm.Config, useBalanceDiffProfit bool) error {
+func (bc *BlockChain) ValidatePayload(ctx context.Context, block *types.Block, feeRecipient common.Address, expectedProfit *big.Int, registeredGasLimit uint64, vmConfig vm.Config, useBalanceDiffProfit bool) error {
+	_, err := bc.ValidatePayloadWithResult(ctx, block, feeRecipient, expectedProfit, registeredGasLimit, vmConfig, useBalanceDiffProfit, false)
+	return err
+}
+
+// PayloadValidationResult holds the by-products of executing a valid payload.
+type PayloadValidationResult struct {
+	// Receipts produced while executing the block.
+	Receipts types.Receipts
+	// Balance change of the fee recipient over the block.
+	FeeRecipientBalanceDelta *big.Int
+	// Proposer payment that was verified against the expected profit.
+	Profit *big.Int
+}
+
+// ValidatePayloadWithResult is like ValidatePayload but also returns the by-products
+// of executing the block, if the payload is valid.
+// If skipStateValidation is set, the post-state and receipts are not checked against the
+// header. This is only meant for blocks whose transactions were altered after sealing.
+// Once the context is done, the execution of the block is aborted with the context error.
+func (bc *BlockChain) ValidatePayloadWithResult(ctx context.Context, block *types.Block, feeRecipient common.Address, expectedProfit *big.Int, registeredGasLimit uint64, vmConfig vm.Config, useBalanceDiffProfit bool, skipStateValidation bool) (*PayloadValidationResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	header := block.Header()
 	if err := bc.engine.VerifyHeader(bc, header, true); err != nil {
-		return fmt.Errorf("invalid block header: %w", err)
+		return nil, fmt.Errorf("invalid block header: %w", err)
 	}
 
 	current := bc.CurrentBlock()
 	reorg, err := bc.forker.ReorgNeeded(current, header)
 	if err == nil && reorg {
-		return errors.New("block requires a reorg")
+		return nil, errors.New("block requires a reorg")
 	}
 
 	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
 	if parent == nil {
-		return errors.New("parent not found")
+		return nil, errors.New("parent not found")
 	}
 
-	calculatedGasLimit := utils.CalcGasLimit(parent.GasLimit, registeredGasLimit)
-	if calculatedGasLimit != header.GasLimit {
-                return fmt.Errorf("incorrect gas limit set, expected: %d, header: %d", calculatedGasLimit, header.GasLimit)
+	if err := VerifyRegisteredGasLimit(header.GasLimit, parent.GasLimit, registeredGasLimit); err != nil {
+		return nil, err
 	}
 
 	statedb, err := bc.StateAt(parent.Root)
 	if err != nil {
-		return fmt.Errorf("can't access state: %w", err)
+		return nil, fmt.Errorf("can't access state: %w", err)
 	}
 
 	// The chain importer is starting and stopping trie prefetchers. If a bad
@@ -2533,9 +2555,17 @@ func (bc *BlockChain) ValidatePayload(block *types.Block, feeRecipient common.Ad
 
 	feeRecipientBalanceBefore := new(big.Int).Set(statedb.GetBalance(feeRecipient))
 
-	receipts, _, usedGas, err := bc.processor.Process(block, statedb, vmConfig)
+	var (
+		receipts types.Receipts
+		usedGas  uint64
+	)
+	if processor, ok := bc.processor.(*StateProcessor); ok {
+		receipts, _, usedGas, err = processor.ProcessContext(ctx, block, statedb, vmConfig)
+	} else {
+		receipts, _, usedGas, err = bc.processor.Process(block, statedb, vmConfig)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to process block: %w", err)
+		return nil, fmt.Errorf("failed to process block: %w", err)
 	}
 
 	feeRecipientBalanceDelta := new(big.Int).Set(statedb.GetBalance(feeRecipient))
@@ -2543,24 +2573,26 @@ func (bc *BlockChain) ValidatePayload(block *types.Block, feeRecipient common.Ad
 
 	if bc.Config().IsShanghai(header.Time) {
 		if header.WithdrawalsHash == nil {
-			return fmt.Errorf("withdrawals hash is missing")
+			return nil, fmt.Errorf("withdrawals hash is missing")
 		}
 		// withdrawals hash and withdrawals validated later in ValidateBody
 	} else {
 		if header.WithdrawalsHash != nil {
-			return fmt.Errorf("withdrawals hash present before shanghai")
+			return nil, fmt.Errorf("withdrawals hash present before shanghai")
 		}
 		if block.Withdrawals() != nil {
-			return fmt.Errorf("withdrawals list present in block body before shanghai")
+			return nil, fmt.Errorf("withdrawals list present in block body before shanghai")
 		}
 	}
 
 	if err := bc.validator.ValidateBody(block); err != nil {
-		return fmt.Errorf("failed to validate block body: %w", err)
+		return nil, fmt.Errorf("failed to validate block body: %w", err)
 	}
 
-	if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
-		return fmt.Errorf("failed to validate block state: %w", err)
+	if !skipStateValidation {
+		if err := bc.validator.ValidateState(block, statedb, receipts, usedGas); err != nil {
+			return nil, fmt.Errorf("failed to validate block state: %w", err)
+		}
 	}
 
 	// Validate proposer payment
@@ -2570,55 +2602,55 @@ func (bc *BlockChain) ValidatePayload(block *types.Block, feeRecipient common.Ad
 			if feeRecipientBalanceDelta.Cmp(expectedProfit) > 0 {
 				log.Warn("builder claimed profit is lower than calculated profit", "expected", expectedProfit, "actual", feeRecipientBalanceDelta)
 			}
-			return nil
+			return &PayloadValidationResult{Receipts: receipts, FeeRecipientBalanceDelta: feeRecipientBalanceDelta, Profit: feeRecipientBalanceDelta}, nil
 		}
 		log.Warn("proposer payment not enough, trying last tx payment validation", "expected", expectedProfit, "actual", feeRecipientBalanceDelta)
 	}
 
 	if len(receipts) == 0 {
-		return errors.New("no proposer payment receipt")
+		return nil, errors.New("no proposer payment receipt")
 	}
 
 	lastReceipt := receipts[len(receipts)-1]
 	if lastReceipt.Status != types.ReceiptStatusSuccessful {
-		return errors.New("proposer payment not successful")
+		return nil, errors.New("proposer payment not successful")
 	}
 	txIndex := lastReceipt.TransactionIndex
 	if txIndex+1 != uint(len(block.Transactions())) {
-		return fmt.Errorf("proposer payment index not last transaction in the block (%d of %d)", txIndex, len(block.Transactions())-1)
+		return nil, fmt.Errorf("proposer payment index not last transaction in the block (%d of %d)", txIndex, len(block.Transactions())-1)
 	}
 
 	paymentTx := block.Transaction(lastReceipt.TxHash)
 	if paymentTx == nil {
-		return errors.New("payment tx not in the block")
+		return nil, errors.New("payment tx not in the block")
 	}
 
 	paymentTo := paymentTx.To()
 	if paymentTo == nil || *paymentTo != feeRecipient {
-		return fmt.Errorf("payment tx not to the proposers fee recipient (%v)", paymentTo)
+		return nil, fmt.Errorf("payment tx not to the proposers fee recipient (%v)", paymentTo)
 	}
 
 	if paymentTx.Value().Cmp(expectedProfit) != 0 {
-		return fmt.Errorf("inaccurate payment %s, expected %s", paymentTx.Value().String(), expectedProfit.String())
+		return nil, fmt.Errorf("%w %s, expected %s", ErrInaccuratePayment, paymentTx.Value().String(), expectedProfit.String())
 	}
 
 	if len(paymentTx.Data()) != 0 {
-		return fmt.Errorf("malformed proposer payment, contains calldata")
+		return nil, fmt.Errorf("malformed proposer payment, contains calldata")
 	}
 
 	if paymentTx.GasPrice().Cmp(block.BaseFee()) != 0 {
-		return fmt.Errorf("malformed proposer payment, gas price not equal to base fee")
+		return nil, fmt.Errorf("malformed proposer payment, gas price not equal to base fee")
 	}
 
 	if paymentTx.GasTipCap().Cmp(block.BaseFee()) != 0 && paymentTx.GasTipCap().Sign() != 0 {
-		return fmt.Errorf("malformed proposer payment, unexpected gas tip cap")
+		return nil, fmt.Errorf("malformed proposer payment, unexpected gas tip cap")
 	}
 
 	if paymentTx.GasFeeCap().Cmp(block.BaseFee()) != 0 {
-		return fmt.Errorf("malformed proposer payment, unexpected gas fee cap")
+		return nil, fmt.Errorf("malformed proposer payment, unexpected gas fee cap")
 	}
 
-	return nil
+	return &PayloadValidationResult{Receipts: receipts, FeeRecipientBalanceDelta: feeRecipientBalanceDelta, Profit: paymentTx.Value()}, nil
 }
 
 // SetTrieFlushInterval configures how often in-memory tries are persisted to disk.
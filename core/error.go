@@ -104,4 +104,8 @@ var (
 	// ErrNegativeValue is a sanity error to ensure no one is able to specify a
 	// transaction with a negative value.
 	ErrNegativeValue = errors.New("negative value")
+
+	// ErrInaccuratePayment is returned by payload validation if the proposer
+	// payment does not match the expected profit.
+	ErrInaccuratePayment = errors.New("inaccurate payment")
 )
@@ -17,7 +17,9 @@
 package core
 
 import (
+	"context"
 	"crypto/ecdsa"
+	"errors"
 	"math/big"
 	"testing"
 
@@ -428,3 +430,43 @@ func GenerateBadBlock(parent *types.Block, engine consensus.Engine, txs types.Tr
 	}
 	return types.NewBlock(header, txs, nil, receipts, trie.NewStackTrie(nil))
 }
+
+// TestStateProcessorContextCanceled tests that processing a block is aborted once the
+// context is done, even while a transaction is executing.
+func TestStateProcessorContextCanceled(t *testing.T) {
+	var (
+		config = params.AllEthashProtocolChanges
+		signer = types.LatestSigner(config)
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		loop   = common.HexToAddress("0xaa")
+		db     = rawdb.NewMemoryDatabase()
+		gspec  = &Genesis{
+			Config:   config,
+			GasLimit: 30_000_000,
+			Alloc: GenesisAlloc{
+				crypto.PubkeyToAddress(key.PublicKey): {Balance: big.NewInt(1000000000000000000)},
+				// JUMPDEST, PUSH1 0, JUMP
+				loop: {Balance: big.NewInt(0), Code: common.FromHex("5b600056")},
+			},
+		}
+		blockchain, _ = NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil)
+	)
+	defer blockchain.Stop()
+
+	tx, _ := types.SignTx(types.NewTransaction(0, loop, big.NewInt(0), 25_000_000, big.NewInt(875000000), nil), signer, key)
+	block := GenerateBadBlock(blockchain.Genesis(), ethash.NewFaker(), types.Transactions{tx}, config)
+	statedb, err := blockchain.StateAt(blockchain.Genesis().Root())
+	if err != nil {
+		t.Fatal(err)
+	}
+	processor := blockchain.Processor().(*StateProcessor)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, _, err := processor.ProcessContext(ctx, block, statedb.Copy(), vm.Config{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("have error %v, want %v", err, context.Canceled)
+	}
+	if _, _, _, err := processor.ProcessContext(context.Background(), block, statedb.Copy(), vm.Config{}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,102 @@
+package blockvalidation
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+const (
+	// defaultAnomalyWindowSize is used when BlockValidationConfig.AnomalyWindowSize is not set.
+	defaultAnomalyWindowSize = 1000
+	// anomalyMinSamples is the number of profits needed before bids are compared against them.
+	anomalyMinSamples = 10
+	// anomalyPrecision is the precision of the running statistics, enough for squared wei values.
+	anomalyPrecision = 256
+)
+
+// profitWindow keeps the running mean and variance of the profits of the latest valid blocks,
+// using Welford's algorithm extended to remove the oldest profit once the window is full.
+type profitWindow struct {
+	mu      sync.Mutex
+	profits []*big.Float // ring buffer of the window
+	next    int
+	count   int
+	mean    *big.Float
+	m2      *big.Float // sum of squared deviations from the mean
+}
+
+func newProfitWindow(size int) *profitWindow {
+	if size <= 0 {
+		size = defaultAnomalyWindowSize
+	}
+	return &profitWindow{
+		profits: make([]*big.Float, size),
+		mean:    newAnomalyFloat(),
+		m2:      newAnomalyFloat(),
+	}
+}
+
+func newAnomalyFloat() *big.Float {
+	return new(big.Float).SetPrec(anomalyPrecision)
+}
+
+// add records the profit of a valid block, replacing the oldest one if the window is full.
+func (w *profitWindow) add(profit *big.Int) {
+	x := newAnomalyFloat().SetInt(profit)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if old := w.profits[w.next]; old != nil {
+		w.remove(old)
+	}
+	w.profits[w.next] = x
+	w.next = (w.next + 1) % len(w.profits)
+
+	w.count++
+	delta := newAnomalyFloat().Sub(x, w.mean)
+	w.mean.Add(w.mean, newAnomalyFloat().Quo(delta, newAnomalyFloat().SetInt64(int64(w.count))))
+	w.m2.Add(w.m2, delta.Mul(delta, newAnomalyFloat().Sub(x, w.mean)))
+}
+
+// remove reverses the addition of x. The lock must be held.
+func (w *profitWindow) remove(x *big.Float) {
+	w.count--
+	if w.count == 0 {
+		w.mean.SetInt64(0)
+		w.m2.SetInt64(0)
+		return
+	}
+	delta := newAnomalyFloat().Sub(x, w.mean)
+	w.mean.Sub(w.mean, newAnomalyFloat().Quo(delta, newAnomalyFloat().SetInt64(int64(w.count))))
+	w.m2.Sub(w.m2, delta.Mul(delta, newAnomalyFloat().Sub(x, w.mean)))
+	// Rounding must not turn the variance negative.
+	if w.m2.Sign() < 0 {
+		w.m2.SetInt64(0)
+	}
+}
+
+// threshold returns mean + sigma * stddev of the profits in the window, false while it holds
+// too few profits.
+func (w *profitWindow) threshold(sigma float64) (*big.Float, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.count < anomalyMinSamples {
+		return nil, false
+	}
+	variance := newAnomalyFloat().Quo(w.m2, newAnomalyFloat().SetInt64(int64(w.count)))
+	stddev := newAnomalyFloat().Sqrt(variance)
+	limit := stddev.Mul(stddev, newAnomalyFloat().SetFloat64(sigma))
+	return limit.Add(limit, w.mean), true
+}
+
+// checkProfitAnomaly returns ErrAnomalousProfit for a claimed profit above the threshold of the window.
+func checkProfitAnomaly(window *profitWindow, sigma float64, profit *big.Int) error {
+	limit, ok := window.threshold(sigma)
+	if !ok || newAnomalyFloat().SetInt(profit).Cmp(limit) <= 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: claimed %s wei, more than %.1f standard deviations above the recent mean", ErrAnomalousProfit, profit, sigma)
+}
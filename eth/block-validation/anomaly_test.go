@@ -0,0 +1,99 @@
+package blockvalidation
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// naiveThreshold computes mean + sigma * stddev of the values directly.
+func naiveThreshold(values []float64, sigma float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var squares float64
+	for _, v := range values {
+		squares += (v - mean) * (v - mean)
+	}
+	return mean + sigma*math.Sqrt(squares/float64(len(values)))
+}
+
+func TestProfitWindow(t *testing.T) {
+	window := newProfitWindow(20)
+	var values []float64
+	for i := 0; i < 50; i++ {
+		v := int64(1e18 + (i%7)*1e15 + i*1e14)
+		window.add(big.NewInt(v))
+		values = append(values, float64(v))
+
+		limit, ok := window.threshold(3)
+		if len(values) < anomalyMinSamples {
+			require.False(t, ok)
+			continue
+		}
+		require.True(t, ok)
+		recent := values
+		if len(recent) > 20 {
+			recent = recent[len(recent)-20:]
+		}
+		got, _ := limit.Float64()
+		require.InEpsilon(t, naiveThreshold(recent, 3), got, 1e-9)
+	}
+}
+
+func TestCheckProfitAnomaly(t *testing.T) {
+	window := newProfitWindow(0)
+	require.Len(t, window.profits, defaultAnomalyWindowSize)
+
+	// Too few profits to compare against.
+	require.NoError(t, checkProfitAnomaly(window, 3, big.NewInt(1e18)))
+
+	for i := 0; i < anomalyMinSamples; i++ {
+		window.add(big.NewInt(int64(90 + 2*(i%2)*10))) // mean 100, stddev 10
+	}
+	require.NoError(t, checkProfitAnomaly(window, 3, big.NewInt(130)))
+	require.ErrorIs(t, checkProfitAnomaly(window, 3, big.NewInt(131)), ErrAnomalousProfit)
+	require.NoError(t, checkProfitAnomaly(window, 4, big.NewInt(131)))
+}
+
+func TestValidateBuilderSubmissionV2_AnomalousProfit(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	profit := big.NewInt(21000 * baseFee.Int64())
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, profit)
+
+	newAPI := func(reject bool) *BlockValidationAPI {
+		api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, AnomalyThresholdSigma: 3, RejectAnomalousProfit: reject})
+		for i := 0; i < anomalyMinSamples; i++ {
+			api.profits.add(big.NewInt(int64(1000 + i)))
+		}
+		return api
+	}
+
+	api := newAPI(true)
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrAnomalousProfit)
+	require.Equal(t, anomalyMinSamples, api.profits.count)
+
+	// Without rejection the bid is only logged, and its profit is part of the window once valid.
+	api = newAPI(false)
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.Equal(t, anomalyMinSamples+1, api.profits.count)
+}
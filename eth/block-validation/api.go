@@ -1,33 +1,359 @@
 package blockvalidation
 
 import (
+	"context"
+	"crypto/ecdsa"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	bellatrixapi "github.com/attestantio/go-builder-client/api/bellatrix"
 	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
 )
 
+type AccessVerifier struct {
+	blacklistedAddresses map[common.Address]struct{}
+}
+
+func (a *AccessVerifier) verifyTraces(tracer *logger.AccessListTracer) error {
+	log.Trace("x", "tracer.AccessList()", tracer.AccessList())
+	for _, accessTuple := range tracer.AccessList() {
+		// TODO: should we ignore common.Address{}?
+		if _, found := a.blacklistedAddresses[accessTuple.Address]; found {
+			log.Info("bundle accesses blacklisted address", "address", accessTuple.Address)
+			return newValidationError(CodeBlacklistedAddress, "blacklisted address %s in execution trace", accessTuple.Address.String())
+		}
+	}
+
+	return nil
+}
+
+func (a *AccessVerifier) isBlacklisted(addr common.Address) error {
+	if _, present := a.blacklistedAddresses[addr]; present {
+		return newValidationError(CodeBlacklistedAddress, "transaction from blacklisted address %s", addr.String())
+	}
+	return nil
+}
+
+func (a *AccessVerifier) verifyTransactions(signer types.Signer, txs types.Transactions) error {
+	for _, tx := range txs {
+		from, err := types.Sender(signer, tx)
+		if err == nil {
+			if _, present := a.blacklistedAddresses[from]; present {
+				return newValidationError(CodeBlacklistedAddress, "transaction from blacklisted address %s", from.String())
+			}
+		}
+		to := tx.To()
+		if to != nil {
+			if _, present := a.blacklistedAddresses[*to]; present {
+				return newValidationError(CodeBlacklistedAddress, "transaction to blacklisted address %s", to.String())
+			}
+		}
+	}
+	return nil
+}
+
+func NewAccessVerifierFromFile(path string) (*AccessVerifier, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var ba BlacklistedAddresses
+	if err := json.Unmarshal(bytes, &ba); err != nil {
+		return nil, err
+	}
+
+	blacklistedAddresses := make(map[common.Address]struct{}, len(ba))
+	for _, address := range ba {
+		blacklistedAddresses[address] = struct{}{}
+	}
+
+	return &AccessVerifier{
+		blacklistedAddresses: blacklistedAddresses,
+	}, nil
+}
+
+type BlacklistedAddresses []common.Address
+
 type BlockValidationConfig struct {
+	BlacklistSourceFilePath string
 	// If set to true, proposer payment is calculated as a balance difference of the fee recipient.
 	UseBalanceDiffProfit bool
+	// Selects how the proposer payment of V2 blocks is verified, overriding UseBalanceDiffProfit.
+	// ProfitModeCoinbaseTransfer implies AllowIndirectPayment.
+	ProfitMode ProfitMode
+	// Multiplier applied to base fee * expected gas usage to derive the minimum acceptable
+	// block value reported by flashbots_expectedBlockValue. Zero disables the policy.
+	ProfitMultiplier float64
+	// Optional beacon client used for the RANDAO, proposer duties and withdrawals checks.
+	BeaconClient BeaconClient `toml:"-"`
+	// Maximum time to wait for the beacon client before skipping a dependent check.
+	BeaconClientTimeout time.Duration
+	// If set, the beacon dependent checks are skipped for this long after BeaconFailureThreshold
+	// consecutive beacon client failures (3 if unset), before the beacon client is tried again.
+	BeaconRecoveryTimeout  time.Duration
+	BeaconFailureThreshold int
+	// Contracts that transactions in V2 submissions may not call or emit logs from.
+	BlockedContractAddresses []common.Address
+	// If set, only submissions paying one of these fee recipients are accepted.
+	AllowedFeeRecipients []common.Address
+	// If set, submissions claiming a value below this are rejected before execution. Fee recipients
+	// in MinProfitByRecipient are held to their own minimum instead.
+	MinProfit            *big.Int
+	MinProfitByRecipient map[common.Address]*big.Int
+	// If set, logs emitted by this contract in V2 submissions must be well-formed beacon chain deposits.
+	DepositContractAddress common.Address
+	// Accept V2 blocks whose coinbase is not the proposer fee recipient, with the proposer paid by a transaction.
+	AllowIndirectPayment bool
+	// Optional source of the expected withdrawal total checked after V2 replay.
+	WithdrawalsOracle WithdrawalsOracle `toml:"-"`
+	// V2 submissions for a slot beyond this number are delayed with exponential backoff. Zero disables throttling.
+	MaxSubmissionsPerSlot int
+	// Transactions removed from V2 blocks before EVM replay. As their effects are missing from the
+	// replay, the state root, receipts root and gas used of such a block are not verified.
+	// This overrides safety checks and is only meant for incident response.
+	SkipTransactionHashes []common.Hash
+	// Optional classifier of the MEV in replayed V2 blocks, blocks with MEV of one of the
+	// BlockedMEVTypes are rejected.
+	MEVClassifier   MEVClassifier `toml:"-"`
+	BlockedMEVTypes []string
+	// Allow V2 requests to activate additional EIPs for the replay. Only meant for test networks.
+	AllowCustomEIPs bool
+	// Allow V2 requests to override vm.Config settings for the replay, see VMConfigOverrides.
+	AllowVMConfigOverrides bool
+	// Limits of the flashbots_subscribe("validationEvents") subscriptions.
+	Events ValidationEventsConfig
+	// If set, every successful V2 validation is recorded by sending a recordValidation(bytes32,uint256,address)
+	// transaction to this contract, described by AuditContractABI and signed with AuditSigningKey.
+	// Each transaction pays gas. They are sent in the background, validations are not recorded
+	// while 256 are waiting to be sent. Requires a full node.
+	AuditContractAddress common.Address
+	AuditContractABI     string
+	AuditSigningKey      *ecdsa.PrivateKey `toml:"-"`
+	// If set to true, transactions in V2 blocks must be ordered by effective tip, highest first. The
+	// protocol does not require any ordering, enable it only for relays with that ordering policy.
+	EnforceGreedyOrdering bool
+	// If set to true, validated V2 blocks are re-encoded as execution payloads, which must be identical
+	// to the submitted payloads. This is an expensive check.
+	EnableRoundTripCheck bool
+	// If set, the replay of V2 blocks is traced and aborted once it calls more unique contracts than this.
+	MaxUniqueContractsAccessed int
+	// If set, the replay of V2 blocks is traced and aborted once it accesses an account or storage
+	// slot whose path in the parent state trie is longer than this.
+	MaxStateTrieDepth int
+	// If set to true, the gas of every transaction in V2 blocks is estimated after replay, and transactions
+	// declaring more than 10% above their estimate are logged. With EnforceNoPadding they are rejected.
+	DetectGasPadding bool
+	EnforceNoPadding bool
+	// If set to true, senders of transactions in validated V2 blocks of a builder must use higher
+	// nonces in later slots. A builder reusing nonces of blocks that lost the auction is rejected.
+	EnforceNonceMonotonicity bool
+	// If set, at least this share of the transactions in V2 blocks must be private, that is not
+	// in the local transaction pool. The share is reported as a metric in any case.
+	MinPrivateTxRatio float64
+	// If set, the bytes read from storage during the V2 replays of the latest slot are reported in
+	// the flashbots/replay_disk_read_bytes gauge. The process I/O counters are used, so reads by the
+	// rest of the node during a replay are included. Only supported on Linux.
+	TrackDiskReads bool
+	// If set to true, the replays of V2 blocks are run with the slot and builder pubkey as pprof
+	// labels, so that profiles can be filtered by them.
+	ProfilingMode bool
+	// If set to true, the transactions of V1 and V2 blocks failing execution are traced one by one
+	// and the first failing transaction is logged.
+	TraceOnFailure bool
+	// If set, the outcomes of this many recent V2 validations are cached by block hash and value,
+	// and returned for resubmissions of the same request without executing the block again.
+	ValidationCacheSize int
+	// If set, V1 and V2 validations are aborted with context.DeadlineExceeded after this long.
+	ValidationTimeout time.Duration
+	// Duration of a slot of the beacon chain, a whole number of seconds. Defaults to 12 seconds.
+	SlotDuration time.Duration
+	// Unix time of the beacon chain genesis, used to derive the start of slots and the slot of the
+	// chain head. Slots can not be checked or bounded without it.
+	BeaconGenesisTime uint64
+	// If set, V1 and V2 payloads not timestamped at the start of a slot after their parent, or for
+	// a slot further than this from the wall clock, are rejected before execution. With
+	// BeaconGenesisTime the slot must be the one of the bid trace.
+	MaxTimestampDrift time.Duration
+	// Maximum number of submissions of a batch validated concurrently. Defaults to the number of CPUs.
+	MaxConcurrentValidations int
+	// Maximum size of the JSON encoding of a V1 or V2 request, checked before it is decoded.
+	// Defaults to 2 MiB.
+	MaxPayloadBytes int
+	// If set, each builder may submit V2 blocks at this rate per second, with bursts of RateBurst
+	// submissions. Submissions beyond the limit are rejected with ErrRateLimited.
+	RateLimit rate.Limit
+	RateBurst int
+	// Builders reaching this number of consecutive failed V2 validations are reported. Zero disables reporting.
+	MaxConsecutiveFailures int
+	// Builders reaching this number of consecutive failed V2 validations are rejected until restart. Zero disables blocking.
+	AutoBlockAfterFailures int
+	// If set, Register fails unless the local chain has this genesis block hash.
+	ExpectedGenesisHash common.Hash
+	// If set, the value of V2 submissions must be within 10% of the gas used times the price per gas
+	// in wei returned by latestAnswer() on this contract, described by PriceOracleABI.
+	PriceOracleAddress common.Address
+	PriceOracleABI     string
+	// BLS pubkey of the relay, against which the withdrawal list signatures of V2 requests are verified.
+	RelayPubkey phase0.BLSPubKey `toml:"-"`
+	// If set to true, the BLS signature of the builder over the bid message is verified before
+	// the payload is executed.
+	VerifyBuilderSignature bool
+	// Genesis fork version of the chain, from which the builder signing domain is computed.
+	// Defaults to the mainnet genesis fork version.
+	GenesisForkVersion phase0.Version `toml:"-"`
+	// Registerer of the Prometheus validation metrics, nil disables them.
+	MetricsRegisterer prometheus.Registerer `toml:"-"`
+	// Callbacks invoked around every V1 and V2 validation.
+	Hooks ValidationHooks `toml:"-"`
+	// If set, every V1 and V2 validation attempt is appended to this file as NDJSON. The file is rotated at
+	// midnight UTC.
+	AuditLogPath string
+	// If set, V2 validation metrics are pushed to this OTLP/HTTP endpoint every 30 seconds.
+	OTLPEndpoint string
+	// If set to true, V2 validation verifies that log indices in the receipts increase monotonically.
+	VerifyLogOrdering bool
+	// If set to true, V2 submissions are also accepted SSZ encoded by flashbots_validateBuilderSubmissionV2SSZ.
+	EnableSSZ bool
+	// Maximum number of builders whose validation stats are kept for flashbots_getBuilderStats. Defaults to 10000.
+	MaxBuilderStats int
+	// If set to true, the builder stats can be cleared with flashbots_resetBuilderStats.
+	AllowBuilderStatsReset bool
+	// If set, the extra data of V2 blocks must start with one of these prefixes.
+	RequiredExtraDataPrefixes [][]byte
+	// flashbots_health reports the node ready to validate only with more peers than this. Defaults to 1.
+	MinPeers int
+	// If set, V1 and V2 blocks with more transactions than this are rejected before execution.
+	MaxTransactionsPerBlock int
+	// Number of latest slots in which resubmissions of the same block for the same slot get the
+	// outcome of the first validation. Defaults to 2.
+	DedupWindowSlots uint64
+	// If set to true, V2 requests sent over a websocket at /flashbots/validation on the HTTP endpoint
+	// are answered with the progress of their validation.
+	EnableProgressWebsocket bool
+	// If set to true, V2 blocks building on a known block outside the canonical chain are validated
+	// on its state. Such blocks are rejected otherwise.
+	AllowNonCanonicalParent bool
+	// Rejects V1 and V2 submissions while the head of the chain is stale.
+	CircuitBreaker CircuitBreakerConfig
+	// Expected coinbase of the V2 blocks of a builder, by BLS pubkey. Builders missing from it can
+	// use any coinbase.
+	BuilderCoinbaseRegistry map[[48]byte]common.Address `toml:"-"`
+	// Filters applied to every transaction of V2 blocks before they are executed, see BlockedRecipientFilter.
+	TxFilters []TransactionFilter `toml:"-"`
+	// If set, V2 submissions are posted to /eth/v1/beacon/blinded_blocks/validate on this beacon node
+	// once their execution succeeded, and rejected if the beacon node does not accept them.
+	BeaconNodeURL string
+	// If set, the claimed profits of V2 submissions more than this many standard deviations above the
+	// mean of the last AnomalyWindowSize valid blocks (1000 if zero) are logged, and rejected if
+	// RejectAnomalousProfit is set.
+	AnomalyThresholdSigma float64
+	AnomalyWindowSize     int
+	RejectAnomalousProfit bool
+	// If set to true, V2 blocks with transactions sent to the zero address are rejected. Contract
+	// creations are not affected.
+	ForbidZeroAddressTransactions bool
 }
 
 // Register adds catalyst APIs to the full node.
 func Register(stack *node.Node, backend *eth.Ethereum, cfg BlockValidationConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	maxPayloadBytes.Store(int64(cfg.MaxPayloadBytes))
+	if cfg.ExpectedGenesisHash != (common.Hash{}) {
+		if err := checkGenesisHash(backend.BlockChain(), cfg.ExpectedGenesisHash); err != nil {
+			return err
+		}
+	}
+
+	var accessVerifier *AccessVerifier
+	if cfg.BlacklistSourceFilePath != "" {
+		var err error
+		accessVerifier, err = NewAccessVerifierFromFile(cfg.BlacklistSourceFilePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	api := newBlockValidationAPI(backend, accessVerifier, cfg)
+	if server := stack.Server(); server != nil {
+		api.peerCount = server.PeerCount
+	}
+	if api.otlp != nil {
+		stack.RegisterLifecycle(api.otlp)
+	}
+	if api.headBreaker != nil {
+		stack.RegisterLifecycle(api.headBreaker)
+	}
+	if cfg.MetricsRegisterer != nil {
+		metrics, err := NewBlockValidationMetrics(cfg.MetricsRegisterer)
+		if err != nil {
+			return err
+		}
+		api.metrics = metrics
+	}
+	if cfg.AuditContractAddress != (common.Address{}) {
+		auditContract, err := newAuditContract(backend, cfg.AuditContractAddress, cfg.AuditContractABI, cfg.AuditSigningKey)
+		if err != nil {
+			return err
+		}
+		api.auditContract = auditContract
+		stack.RegisterLifecycle(auditContract)
+	}
+	if cfg.PriceOracleAddress != (common.Address{}) {
+		priceOracle, err := newPriceOracle(cfg.PriceOracleAddress, cfg.PriceOracleABI)
+		if err != nil {
+			return err
+		}
+		api.priceOracle = priceOracle
+	}
+	if cfg.AuditLogPath != "" {
+		auditLog, err := newAuditLog(cfg.AuditLogPath)
+		if err != nil {
+			return err
+		}
+		api.audit = auditLog
+		stack.RegisterLifecycle(auditLog)
+	}
+
+	if cfg.EnableProgressWebsocket {
+		stack.RegisterHandler("Block validation progress", progressWebsocketPath, &progressHandler{api: api})
+	}
+
 	stack.RegisterAPIs([]rpc.API{
 		{
 			Namespace: "flashbots",
-			Service:   NewBlockValidationAPI(backend, cfg.UseBalanceDiffProfit),
+			Service:   api,
 		},
 	})
 	return nil
@@ -35,17 +361,141 @@ func Register(stack *node.Node, backend *eth.Ethereum, cfg BlockValidationConfig
 
 type BlockValidationAPI struct {
 	eth            *eth.Ethereum
+	chain          *core.BlockChain
+	accessVerifier *AccessVerifier
 	// If set to true, proposer payment is calculated as a balance difference of the fee recipient.
 	useBalanceDiffProfit bool
+	cfg                  BlockValidationConfig
+	otlp                 *otlpExporter
+	audit                *auditLog
+	throttler            *SlotThrottler
+	events               *eventHub
+	auditContract        *auditContract
+	failures             *failureTracker
+	beaconBreaker        *CircuitBreaker
+	priceOracle          *priceOracle
+	nonces               *nonceTracker
+	diskIO               *diskIOTracker
+	metrics              *BlockValidationMetrics
+	cache                *validationCache
+	builderDomain        *phase0.Domain
+	allowedFeeRecipients map[common.Address]struct{}
+	rateLimiter          *builderRateLimiter
+	builderStats         *builderStatsTracker
+	// Number of connected peers, nil without a p2p server.
+	peerCount   func() int
+	dedup       *slotDedup
+	headBreaker *headBreaker
+	beaconNode  *beaconNodeClient
+	profits     *profitWindow
 }
 
 // NewConsensusAPI creates a new consensus api for the given backend.
 // The underlying blockchain needs to have a valid terminal total difficulty set.
-func NewBlockValidationAPI(eth *eth.Ethereum, useBalanceDiffProfit bool) *BlockValidationAPI {
-	return &BlockValidationAPI{
+// The validation metrics are registered with the registerer unless it is nil.
+func NewBlockValidationAPI(eth *eth.Ethereum, accessVerifier *AccessVerifier, useBalanceDiffProfit bool, registerer prometheus.Registerer) (*BlockValidationAPI, error) {
+	api := newBlockValidationAPI(eth, accessVerifier, BlockValidationConfig{UseBalanceDiffProfit: useBalanceDiffProfit, AllowIndirectPayment: true})
+	if registerer != nil {
+		metrics, err := NewBlockValidationMetrics(registerer)
+		if err != nil {
+			return nil, err
+		}
+		api.metrics = metrics
+	}
+	return api, nil
+}
+
+func newBlockValidationAPI(eth *eth.Ethereum, accessVerifier *AccessVerifier, cfg BlockValidationConfig) *BlockValidationAPI {
+	api := &BlockValidationAPI{
 		eth:                  eth,
-		useBalanceDiffProfit: useBalanceDiffProfit,
+		accessVerifier:       accessVerifier,
+		useBalanceDiffProfit: cfg.UseBalanceDiffProfit,
+		cfg:                  cfg,
+	}
+	if eth != nil {
+		api.chain = eth.BlockChain()
+	}
+	if cfg.OTLPEndpoint != "" {
+		api.otlp = newOTLPExporter(cfg.OTLPEndpoint)
+	}
+	api.events = newEventHub(cfg.Events)
+	api.builderStats = newBuilderStatsTracker(cfg.MaxBuilderStats)
+	api.dedup = newSlotDedup(cfg.DedupWindowSlots)
+	if cfg.BeaconNodeURL != "" {
+		api.beaconNode = newBeaconNodeClient(cfg.BeaconNodeURL)
+	}
+	if cfg.AnomalyThresholdSigma > 0 {
+		api.profits = newProfitWindow(cfg.AnomalyWindowSize)
+	}
+	if cfg.CircuitBreaker.MaxHeadAgeSeconds > 0 && api.chain != nil {
+		api.headBreaker = newHeadBreaker(cfg.CircuitBreaker, api.slotDuration(), api.chain.CurrentBlock)
 	}
+	if cfg.MaxSubmissionsPerSlot > 0 {
+		api.throttler = NewSlotThrottler(cfg.MaxSubmissionsPerSlot, defaultThrottleBaseDelay, defaultThrottleMaxDelay)
+	}
+	if cfg.BeaconRecoveryTimeout > 0 {
+		threshold := cfg.BeaconFailureThreshold
+		if threshold <= 0 {
+			threshold = defaultBeaconFailureThreshold
+		}
+		api.beaconBreaker = NewCircuitBreaker(threshold, cfg.BeaconRecoveryTimeout)
+	}
+	if cfg.EnforceNonceMonotonicity {
+		api.nonces = newNonceTracker()
+	}
+	if cfg.TrackDiskReads {
+		if _, err := procReadBytes(); err != nil {
+			log.Warn("disk read tracking not supported, disabling it", "err", err)
+		} else {
+			api.diskIO = newDiskIOTracker()
+		}
+	}
+	if cfg.VerifyBuilderSignature {
+		domain := builderSigningDomain(cfg.GenesisForkVersion)
+		api.builderDomain = &domain
+	}
+	if len(cfg.AllowedFeeRecipients) > 0 {
+		api.allowedFeeRecipients = make(map[common.Address]struct{}, len(cfg.AllowedFeeRecipients))
+		for _, feeRecipient := range cfg.AllowedFeeRecipients {
+			api.allowedFeeRecipients[feeRecipient] = struct{}{}
+		}
+	}
+	if cfg.RateLimit > 0 {
+		api.rateLimiter = newBuilderRateLimiter(cfg.RateLimit, cfg.RateBurst)
+	}
+	if cfg.ValidationCacheSize > 0 {
+		api.cache = newValidationCache(cfg.ValidationCacheSize)
+	}
+	if cfg.MaxConsecutiveFailures > 0 || cfg.AutoBlockAfterFailures > 0 {
+		api.failures = newFailureTracker(cfg.MaxConsecutiveFailures, cfg.AutoBlockAfterFailures)
+	}
+	return api
+}
+
+// ExpectedBlockValue returns the minimum acceptable proposer payment for the given block number.
+// The value is the base fee of the block multiplied by its gas target, scaled by the configured
+// ProfitMultiplier. Nil is returned when no profit policy is configured.
+func (api *BlockValidationAPI) ExpectedBlockValue(blockNumber uint64) (*big.Int, error) {
+	if api.cfg.ProfitMultiplier == 0 {
+		return nil, nil
+	}
+	if blockNumber == 0 {
+		return nil, errors.New("no expected value for genesis block")
+	}
+
+	bc := api.chain
+	parent := bc.GetHeaderByNumber(blockNumber - 1)
+	if parent == nil {
+		return nil, fmt.Errorf("parent of block %d not found", blockNumber)
+	}
+
+	baseFee := misc.CalcBaseFee(bc.Config(), parent)
+	expectedGas := parent.GasLimit / params.DefaultElasticityMultiplier
+
+	value := new(big.Float).SetInt(new(big.Int).Mul(baseFee, new(big.Int).SetUint64(expectedGas)))
+	value.Mul(value, big.NewFloat(api.cfg.ProfitMultiplier))
+	result, _ := value.Int(nil)
+	return result, nil
 }
 
 type BuilderBlockValidationRequest struct {
@@ -53,60 +503,198 @@ type BuilderBlockValidationRequest struct {
 	RegisteredGasLimit uint64 `json:"registered_gas_limit,string"`
 }
 
-func (api *BlockValidationAPI) ValidateBuilderSubmissionV1(params *BuilderBlockValidationRequest) error {
+func (r *BuilderBlockValidationRequest) UnmarshalJSON(data []byte) error {
+	if err := checkPayloadSize(data); err != nil {
+		return err
+	}
+	params := &struct {
+		RegisteredGasLimit uint64 `json:"registered_gas_limit,string"`
+	}{}
+	if err := json.Unmarshal(data, params); err != nil {
+		return err
+	}
+	r.RegisteredGasLimit = params.RegisteredGasLimit
+
+	blockRequest := new(bellatrixapi.SubmitBlockRequest)
+	if err := json.Unmarshal(data, blockRequest); err != nil {
+		return err
+	}
+	r.SubmitBlockRequest = *blockRequest
+	return nil
+}
+
+// ValidateBuilderSubmissionV1 validates a bellatrix submission. The validation is aborted with
+// the context error once the context is done.
+func (api *BlockValidationAPI) ValidateBuilderSubmissionV1(ctx context.Context, params *BuilderBlockValidationRequest) error {
+	_, _, err := api.validateBuilderSubmissionV1(ctx, params)
+	return err
+}
+
+// ValidateBuilderSubmissionV1WithResult validates the submission like ValidateBuilderSubmissionV1
+// and returns the outcome of executing the block if it is valid.
+func (api *BlockValidationAPI) ValidateBuilderSubmissionV1WithResult(ctx context.Context, params *BuilderBlockValidationRequest) (*ValidatePayloadResult, error) {
+	block, result, err := api.validateBuilderSubmissionV1(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return newValidatePayloadResult(block, result), nil
+}
+
+func (api *BlockValidationAPI) validateBuilderSubmissionV1(ctx context.Context, params *BuilderBlockValidationRequest) (block *types.Block, result *core.PayloadValidationResult, err error) {
+	if params == nil {
+		return nil, nil, newValidationError(CodeNilRequest, "nil request")
+	}
+	// Every attempt past this point is audited and observed, including those rejected before
+	// validation.
+	if api.audit != nil {
+		defer func() {
+			api.audit.record(newAuditEntry(params.Message, block, result, err))
+		}()
+	}
+	if api.metrics != nil {
+		defer func(start time.Time) {
+			api.metrics.observe("v1", time.Since(start), params.Message, err)
+		}(time.Now())
+	}
+	if api.headBreaker != nil {
+		if err := api.headBreaker.allow(); err != nil {
+			return nil, nil, err
+		}
+	}
+	ctx, cancel := api.withValidationTimeout(ctx)
+	defer cancel()
+
+	if err := api.cfg.Hooks.preValidation(params); err != nil {
+		return block, nil, err
+	}
+	defer func(start time.Time) {
+		api.cfg.Hooks.finish(params, ValidationOutcome{Block: block, Result: result, Duration: time.Since(start)}, err)
+	}(time.Now())
+	defer func(start time.Time) {
+		api.builderStats.record(params.Message, time.Since(start), err)
+	}(time.Now())
+
 	// TODO: fuzztest, make sure the validation is sound
-	// TODO: handle context!
 
 	if params.ExecutionPayload == nil {
-		return errors.New("nil execution payload")
+		return block, nil, newValidationError(CodeNilPayload, "nil execution payload")
+	}
+	if err := api.checkMinProfit(params.Message); err != nil {
+		return block, nil, err
 	}
 	payload := params.ExecutionPayload
-	block, err := engine.ExecutionPayloadToBlock(payload)
+	if api.builderDomain != nil {
+		if err := verifyBuilderSignature(params.Message, params.Signature, *api.builderDomain); err != nil {
+			return block, nil, err
+		}
+	}
+	// An empty list is valid, but a missing one points to a malformed request.
+	if payload.Transactions == nil {
+		return block, nil, ErrNilTransactions
+	}
+	if err := api.checkPayloadTimestamp(params.Message, common.Hash(payload.ParentHash), payload.Timestamp); err != nil {
+		return block, nil, err
+	}
+	block, err = engine.ExecutionPayloadToBlock(payload)
 	if err != nil {
-		return err
+		return block, nil, err
 	}
 
-	if params.Message.ParentHash != phase0.Hash32(block.ParentHash()) {
-		return fmt.Errorf("incorrect ParentHash %s, expected %s", params.Message.ParentHash.String(), block.ParentHash().String())
+	if err := checkTransactionCount(len(block.Transactions()), api.cfg.MaxTransactionsPerBlock); err != nil {
+		return block, nil, err
 	}
 
-	if params.Message.BlockHash != phase0.Hash32(block.Hash()) {
-		return fmt.Errorf("incorrect BlockHash %s, expected %s", params.Message.BlockHash.String(), block.Hash().String())
+	if err := checkPayloadVersion(api.chain.Config(), block, spec.DataVersionBellatrix); err != nil {
+		return block, nil, err
 	}
 
-	if params.Message.GasLimit != block.GasLimit() {
-		return fmt.Errorf("incorrect GasLimit %d, expected %d", params.Message.GasLimit, block.GasLimit())
+	if err := checkMergeActivated(api.chain, block); err != nil {
+		return block, nil, err
 	}
 
-	if params.Message.GasUsed != block.GasUsed() {
-		return fmt.Errorf("incorrect GasUsed %d, expected %d", params.Message.GasUsed, block.GasUsed())
+	if err := checkBaseFee(api.chain, block); err != nil {
+		return block, nil, err
+	}
+
+	checkGasLimit := func() error { return api.checkRegisteredGasLimit(block, params.RegisteredGasLimit) }
+	if err := validateCommonFields(bidTraceMessage{params.Message}, block, checkGasLimit); err != nil {
+		return block, nil, err
+	}
+
+	if _, err := api.verifyWithBeaconClient(ctx, params.Message, block); err != nil {
+		return block, nil, err
 	}
 
 	feeRecipient := common.BytesToAddress(params.Message.ProposerFeeRecipient[:])
+	if err := api.checkFeeRecipientAllowed(feeRecipient); err != nil {
+		return block, nil, err
+	}
 	expectedProfit := params.Message.Value.ToBig()
 
 	var vmconfig vm.Config
+	var tracer *logger.AccessListTracer = nil
+	if api.accessVerifier != nil {
+		if err := api.accessVerifier.isBlacklisted(block.Coinbase()); err != nil {
+			return block, nil, err
+		}
+		if err := api.accessVerifier.isBlacklisted(feeRecipient); err != nil {
+			return block, nil, err
+		}
+		if err := api.accessVerifier.verifyTransactions(types.LatestSigner(api.chain.Config()), block.Transactions()); err != nil {
+			return block, nil, err
+		}
+		isPostMerge := true // the call is PoS-native
+		timestamp := params.ExecutionPayload.Timestamp
+		precompiles := vm.ActivePrecompiles(api.chain.Config().Rules(new(big.Int).SetUint64(params.ExecutionPayload.BlockNumber), isPostMerge, timestamp))
+		tracer = logger.NewAccessListTracer(nil, common.Address{}, common.Address{}, precompiles)
+		vmconfig = vm.Config{Tracer: tracer, Debug: true}
+	}
 
-	err = api.eth.BlockChain().ValidatePayload(block, feeRecipient, expectedProfit, params.RegisteredGasLimit, vmconfig, api.useBalanceDiffProfit)
+	result, err = api.chain.ValidatePayloadWithResult(ctx, block, feeRecipient, expectedProfit, params.RegisteredGasLimit, vmconfig, api.useBalanceDiffProfit, false)
 	if err != nil {
 		log.Error("invalid payload", "hash", payload.BlockHash.String(), "number", payload.BlockNumber, "parentHash", payload.ParentHash.String(), "err", err)
-		return err
+		if api.cfg.TraceOnFailure && ctx.Err() == nil {
+			api.traceFailure(ctx, block, err)
+		}
+		if errors.Is(err, core.ErrInaccuratePayment) {
+			return block, nil, wrapValidationError(CodeInsufficientProfit, err)
+		}
+		return block, nil, err
+	}
+
+	if api.accessVerifier != nil && tracer != nil {
+		if err := api.accessVerifier.verifyTraces(tracer); err != nil {
+			return block, nil, err
+		}
 	}
 
 	log.Info("validated block", "hash", block.Hash(), "number", block.NumberU64(), "parentHash", block.ParentHash())
-	return nil
+	return block, result, nil
 }
 
 type BuilderBlockValidationRequestV2 struct {
 	capellaapi.SubmitBlockRequest
 	RegisteredGasLimit uint64      `json:"registered_gas_limit,string"`
 	WithdrawalsRoot    common.Hash `json:"withdrawals_root"`
+	// Additional EIPs to activate during replay, only accepted if AllowCustomEIPs is set.
+	ExtraEIPs []int `json:"extra_eips,omitempty"`
+	// Optional BLS signature of the relay over the hash tree root of the payload withdrawals,
+	// verified against BlockValidationConfig.RelayPubkey.
+	WithdrawalListSignature hexutil.Bytes `json:"withdrawal_list_signature,omitempty"`
+	// Optional vm.Config settings of the replay, only accepted if AllowVMConfigOverrides is set.
+	VMConfigOverrides *VMConfigOverrides `json:"vm_config_overrides,omitempty"`
 }
 
 func (r *BuilderBlockValidationRequestV2) UnmarshalJSON(data []byte) error {
+	if err := checkPayloadSize(data); err != nil {
+		return err
+	}
 	params := &struct {
-		RegisteredGasLimit uint64      `json:"registered_gas_limit,string"`
-		WithdrawalsRoot    common.Hash `json:"withdrawals_root"`
+		RegisteredGasLimit      uint64             `json:"registered_gas_limit,string"`
+		WithdrawalsRoot         common.Hash        `json:"withdrawals_root"`
+		ExtraEIPs               []int              `json:"extra_eips"`
+		WithdrawalListSignature hexutil.Bytes      `json:"withdrawal_list_signature"`
+		VMConfigOverrides       *VMConfigOverrides `json:"vm_config_overrides"`
 	}{}
 	err := json.Unmarshal(data, params)
 	if err != nil {
@@ -114,6 +702,9 @@ func (r *BuilderBlockValidationRequestV2) UnmarshalJSON(data []byte) error {
 	}
 	r.RegisteredGasLimit = params.RegisteredGasLimit
 	r.WithdrawalsRoot = params.WithdrawalsRoot
+	r.ExtraEIPs = params.ExtraEIPs
+	r.WithdrawalListSignature = params.WithdrawalListSignature
+	r.VMConfigOverrides = params.VMConfigOverrides
 
 	blockRequest := new(capellaapi.SubmitBlockRequest)
 	err = json.Unmarshal(data, &blockRequest)
@@ -124,51 +715,912 @@ func (r *BuilderBlockValidationRequestV2) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (api *BlockValidationAPI) ValidateBuilderSubmissionV2(params *BuilderBlockValidationRequestV2) error {
+// defaultMaxPayloadBytes is used when BlockValidationConfig.MaxPayloadBytes is not set.
+const defaultMaxPayloadBytes = 2 << 20
+
+// maxPayloadBytes is the MaxPayloadBytes of the registered API. Requests are decoded before they
+// reach the API, so the limit can not be read from its config.
+var maxPayloadBytes atomic.Int64
+
+// checkPayloadSize rejects requests larger than MaxPayloadBytes before they are decoded.
+func checkPayloadSize(data []byte) error {
+	limit := maxPayloadBytes.Load()
+	if limit <= 0 {
+		limit = defaultMaxPayloadBytes
+	}
+	if int64(len(data)) > limit {
+		return fmt.Errorf("%w: %d bytes, at most %d allowed", ErrPayloadTooLarge, len(data), limit)
+	}
+	return nil
+}
+
+// ComputeWithdrawalsRoot returns the Merkle root of the given withdrawals list,
+// as committed to by the withdrawalsRoot field of the block header.
+func ComputeWithdrawalsRoot(withdrawals []*types.Withdrawal) common.Hash {
+	return types.DeriveSha(types.Withdrawals(withdrawals), trie.NewStackTrie(nil))
+}
+
+// skipTransactions removes the transactions with the given hashes from the block. The returned
+// block has its transactions root updated, but its other header fields still commit to the
+// state of the original block.
+func skipTransactions(block *types.Block, hashes []common.Hash) (*types.Block, bool) {
+	if len(hashes) == 0 {
+		return block, false
+	}
+	skip := make(map[common.Hash]struct{}, len(hashes))
+	for _, hash := range hashes {
+		skip[hash] = struct{}{}
+	}
+
+	var txs types.Transactions
+	for _, tx := range block.Transactions() {
+		if _, found := skip[tx.Hash()]; found {
+			log.Warn("skipping transaction in block validation", "block", block.Hash(), "tx", tx.Hash())
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	if len(txs) == len(block.Transactions()) {
+		return block, false
+	}
+
+	header := block.Header()
+	header.TxHash = types.DeriveSha(txs, trie.NewStackTrie(nil))
+	return types.NewBlockWithHeader(header).WithBody(txs, block.Uncles()).WithWithdrawals(block.Withdrawals()), true
+}
+
+// ValidateBuilderSubmissionV2 validates a capella submission. The validation is aborted with
+// the context error once the context is done.
+func (api *BlockValidationAPI) ValidateBuilderSubmissionV2(ctx context.Context, params *BuilderBlockValidationRequestV2) error {
+	_, _, err := api.validateBuilderSubmissionV2(ctx, params, nil)
+	return err
+}
+
+// ValidateBuilderSubmissionV2WithResult validates the submission like ValidateBuilderSubmissionV2
+// and returns the outcome of executing the block if it is valid.
+func (api *BlockValidationAPI) ValidateBuilderSubmissionV2WithResult(ctx context.Context, params *BuilderBlockValidationRequestV2) (*ValidatePayloadResult, error) {
+	block, result, err := api.validateBuilderSubmissionV2(ctx, params, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newValidatePayloadResult(block, result), nil
+}
+
+// ValidatePayloadResult is the outcome of executing a valid block.
+type ValidatePayloadResult struct {
+	// Proposer payment verified against the claimed value.
+	ComputedProfit *big.Int `json:"computed_profit"`
+	GasUsed        uint64   `json:"gas_used"`
+	// Balance change of the fee recipient over the block.
+	FeeRecipientDelta *big.Int    `json:"fee_recipient_delta"`
+	BlockHash         common.Hash `json:"block_hash"`
+	// Gas used over the gas limit of the block, between 0 and 1.
+	GasEfficiency float64      `json:"gas_efficiency"`
+	Transactions  []TxGasEntry `json:"transactions"`
+}
+
+// TxGasEntry is the gas used by a transaction of a valid block and the price it paid per gas.
+type TxGasEntry struct {
+	TxHash   common.Hash `json:"tx_hash"`
+	GasUsed  uint64      `json:"gas_used"`
+	GasPrice *big.Int    `json:"gas_price"`
+}
+
+// newValidatePayloadResult summarizes the execution of a valid block from the receipts produced
+// while validating it, the block is not executed again.
+func newValidatePayloadResult(block *types.Block, result *core.PayloadValidationResult) *ValidatePayloadResult {
+	res := &ValidatePayloadResult{
+		ComputedProfit:    result.Profit,
+		GasUsed:           block.GasUsed(),
+		FeeRecipientDelta: result.FeeRecipientBalanceDelta,
+		BlockHash:         block.Hash(),
+		Transactions:      make([]TxGasEntry, 0, len(result.Receipts)),
+	}
+	if block.GasLimit() > 0 {
+		res.GasEfficiency = float64(block.GasUsed()) / float64(block.GasLimit())
+	}
+	// The receipts are those of the replayed block, which lacks any skipped transactions.
+	txs := make(map[common.Hash]*types.Transaction, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		txs[tx.Hash()] = tx
+	}
+	for _, receipt := range result.Receipts {
+		entry := TxGasEntry{TxHash: receipt.TxHash, GasUsed: receipt.GasUsed}
+		if tx, ok := txs[receipt.TxHash]; ok {
+			entry.GasPrice = effectiveGasPrice(tx, block.BaseFee())
+		}
+		res.Transactions = append(res.Transactions, entry)
+	}
+	return res
+}
+
+// effectiveGasPrice returns the price per gas the transaction paid with the base fee.
+func effectiveGasPrice(tx *types.Transaction, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return tx.GasPrice()
+	}
+	return new(big.Int).Add(baseFee, tx.EffectiveGasTipValue(baseFee))
+}
+
+// validateBuilderSubmissionV2 validates the submission and returns the block converted from the
+// execution payload together with the result of executing it. The block is nil if the payload
+// could not be converted, the result is nil unless the block was executed successfully.
+// Simulations pass a tracer of the replay, they bypass the cache and are not counted as failures
+// of the builder.
+func (api *BlockValidationAPI) validateBuilderSubmissionV2(ctx context.Context, params *BuilderBlockValidationRequestV2, simulation *simulationTracer) (block *types.Block, result *core.PayloadValidationResult, err error) {
+	if params == nil {
+		log.Error("nil request")
+		return nil, nil, newValidationError(CodeNilRequest, "nil request")
+	}
+	if params.Message == nil {
+		log.Error("nil bid message")
+		return nil, nil, newValidationError(CodeNilMessage, "nil bid message")
+	}
+	// Every attempt past this point is audited and observed, including those rejected before
+	// validation. The latency includes the throttle delay.
+	if api.audit != nil {
+		defer func() {
+			api.audit.record(newAuditEntry(params.Message, block, result, err))
+		}()
+	}
+	if api.otlp != nil {
+		defer func(start time.Time) {
+			api.otlp.observe(time.Since(start), params.Message, result, err)
+		}(time.Now())
+	}
+	if api.metrics != nil {
+		defer func(start time.Time) {
+			api.metrics.observe("v2", time.Since(start), params.Message, err)
+		}(time.Now())
+	}
+	defer func(start time.Time) {
+		api.events.publish(newValidationEvent(params.Message, time.Since(start), err))
+	}(time.Now())
+	if api.headBreaker != nil {
+		if err := api.headBreaker.allow(); err != nil {
+			log.Error("rejecting submission on a stale head", "err", err)
+			return nil, nil, err
+		}
+	}
+	if api.rateLimiter != nil {
+		if !api.rateLimiter.allow(params.Message.BuilderPubkey, params.Message.Slot, api.maxSlot()) {
+			log.Error("rejecting rate limited builder", "builder", params.Message.BuilderPubkey.String())
+			return nil, nil, ErrRateLimited{Builder: params.Message.BuilderPubkey}
+		}
+	}
+	if api.throttler != nil {
+		slot := params.Message.Slot
+		if maxSlot := api.maxSlot(); slot > maxSlot {
+			slot = maxSlot
+		}
+		if delay := api.throttler.reserve(slot); delay > 0 {
+			log.Warn("throttling submission", "slot", params.Message.Slot, "builder", params.Message.BuilderPubkey.String(), "delay", delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	ctx, cancel := api.withValidationTimeout(ctx)
+	defer cancel()
+
+	if err := api.cfg.Hooks.preValidation(params); err != nil {
+		return nil, nil, err
+	}
+	defer func(start time.Time) {
+		api.cfg.Hooks.finish(params, ValidationOutcome{Block: block, Result: result, Duration: time.Since(start)}, err)
+	}(time.Now())
+
+	if api.failures != nil && simulation == nil {
+		if api.failures.isBlocked(params.Message.BuilderPubkey) {
+			log.Error("rejecting blocked builder", "builder", params.Message.BuilderPubkey.String())
+			return nil, nil, ErrBuilderBlocked{Builder: params.Message.BuilderPubkey}
+		}
+		defer func() {
+			api.failures.record(params.Message.BuilderPubkey, err)
+		}()
+	}
+	if simulation == nil {
+		defer func(start time.Time) {
+			api.builderStats.record(params.Message, time.Since(start), err)
+		}(time.Now())
+	}
+
 	// TODO: fuzztest, make sure the validation is sound
-	// TODO: handle context!
 	if params.ExecutionPayload == nil {
 		log.Error("nil execution payload")
-		return errors.New("nil execution payload")
+		return nil, nil, newValidationError(CodeNilPayload, "nil execution payload")
+	}
+	if err := api.checkMinProfit(params.Message); err != nil {
+		log.Error("bid value below minimum", "err", err)
+		return nil, nil, err
 	}
 	payload := params.ExecutionPayload
-	block, err := engine.ExecutionPayloadV2ToBlock(payload)
+
+	// Checked ahead of the cache, so that overrides are never answered with an earlier outcome.
+	if params.VMConfigOverrides != nil && !api.cfg.AllowVMConfigOverrides {
+		log.Error("VM config overrides not allowed")
+		return nil, nil, ErrVMConfigOverridesNotAllowed
+	}
+
+	// Outcomes of submissions some beacon check was skipped for are not cached, as the check may
+	// run on a retry.
+	var beaconSkipped bool
+	if api.cache != nil && simulation == nil {
+		key := newValidationCacheKey(params)
+		digest, digestErr := requestDigest(params)
+		if digestErr == nil {
+			if entry, ok := api.cache.get(key, digest, api.chain.CurrentHeader().Number.Uint64(), api.chain.GetCanonicalHash); ok {
+				log.Debug("returning cached validation outcome", "hash", payload.BlockHash.String(), "err", entry.err)
+				return entry.block, entry.result, entry.err
+			}
+			defer func() {
+				if isCacheable(err) && !beaconSkipped {
+					api.cache.add(key, &validationCacheEntry{digest: digest, number: payload.BlockNumber, parentHash: common.Hash(payload.ParentHash), block: block, result: result, err: err})
+				}
+			}()
+		}
+	}
+	if simulation == nil {
+		slot, blockHash := params.Message.Slot, common.Hash(params.Message.BlockHash)
+		digest, digestErr := requestDigest(params)
+		if digestErr == nil {
+			if entry, ok := api.dedup.get(slot, blockHash, digest); ok {
+				log.Debug("returning outcome of duplicate submission", "slot", slot, "hash", blockHash.String(), "err", entry.err)
+				return entry.block, entry.result, entry.err
+			}
+			defer func() {
+				if isCacheable(err) && !beaconSkipped {
+					api.dedup.add(slot, api.maxSlot(), blockHash, &validationCacheEntry{digest: digest, number: payload.BlockNumber, block: block, result: result, err: err})
+				}
+			}()
+		}
+	}
+	if api.builderDomain != nil {
+		if err := verifyBuilderSignature(params.Message, params.Signature, *api.builderDomain); err != nil {
+			log.Error("invalid builder signature", "err", err)
+			return nil, nil, err
+		}
+	}
+	if err := api.checkPayloadTimestamp(params.Message, common.Hash(payload.ParentHash), payload.Timestamp); err != nil {
+		log.Error("invalid timestamp", "err", err)
+		return nil, nil, err
+	}
+	block, err = engine.ExecutionPayloadV2ToBlock(payload)
 	if err != nil {
 		log.Error("Could not convert payload to block", "err", err)
-		return err
+		return nil, nil, err
+	}
+	reportProgress(ctx, ValidationProgress{Stage: stagePayloadConverted})
+
+	if err := checkTransactionCount(len(block.Transactions()), api.cfg.MaxTransactionsPerBlock); err != nil {
+		log.Error("too many transactions", "err", err)
+		return block, nil, err
+	}
+
+	if err := checkPayloadVersion(api.chain.Config(), block, spec.DataVersionCapella); err != nil {
+		log.Error("invalid payload version", "err", err)
+		return block, nil, err
+	}
+
+	if err := api.checkParent(block); err != nil {
+		log.Error("invalid parent", "err", err)
+		return block, nil, err
+	}
+
+	if err := checkMergeActivated(api.chain, block); err != nil {
+		log.Error("merge not activated", "err", err)
+		return block, nil, err
+	}
+
+	if err := checkDifficulty(api.chain, block); err != nil {
+		log.Error("invalid difficulty", "err", err)
+		return block, nil, err
+	}
+
+	if err := checkBaseFee(api.chain, block); err != nil {
+		log.Error("invalid base fee", "err", err)
+		return block, nil, err
+	}
+
+	checkGasLimit := func() error { return api.checkRegisteredGasLimit(block, params.RegisteredGasLimit) }
+	if err := validateCommonFields(bidTraceMessage{params.Message}, block, checkGasLimit); err != nil {
+		log.Error("incorrect bid trace", "err", err)
+		return block, nil, err
+	}
+
+	skipped, err := api.verifyWithBeaconClient(ctx, params.Message, block)
+	if err != nil {
+		log.Error("beacon client check failed", "err", err)
+		return block, nil, err
+	}
+	beaconSkipped = beaconSkipped || skipped
+
+	if err := checkWithdrawalsCount(block.Withdrawals()); err != nil {
+		log.Error("invalid withdrawals", "err", err)
+		return block, nil, err
+	}
+
+	if err := checkWithdrawalIndices(block.Withdrawals()); err != nil {
+		log.Error("invalid withdrawals", "err", err)
+		return block, nil, err
+	}
+
+	if err := checkWithdrawalsRoot(block.Withdrawals(), params.WithdrawalsRoot); err != nil {
+		log.Error("incorrect withdrawals root", "err", err)
+		return block, nil, err
+	}
+
+	if err := checkWithdrawalsOrdering(block.Withdrawals()); err != nil {
+		log.Error("invalid withdrawals", "err", err)
+		return block, nil, err
+	}
+
+	if params.WithdrawalListSignature != nil {
+		if err := verifyWithdrawalListSignature(payload.Withdrawals, params.WithdrawalListSignature, api.cfg.RelayPubkey); err != nil {
+			log.Error("invalid withdrawal list signature", "err", err)
+			return block, nil, err
+		}
+	}
+
+	if err := checkBlockTransactions(block, params.VMConfigOverrides.vmConfig().NoBaseFee); err != nil {
+		log.Error("invalid transactions", "err", err)
+		return block, nil, err
+	}
+
+	if api.eth != nil && len(block.Transactions()) > 0 {
+		ratio := privateTxRatio(block.Transactions(), func(hash common.Hash) bool { return api.eth.TxPool().Get(hash) != nil })
+		privateTxRatioGauge.Update(ratio)
+		if err := checkPrivateTxRatio(ratio, api.cfg.MinPrivateTxRatio); err != nil {
+			log.Error("too many public transactions", "err", err)
+			return block, nil, err
+		}
+	}
+
+	if api.nonces != nil {
+		if err := api.nonces.check(params.Message.BuilderPubkey, params.Message.Slot, types.LatestSigner(api.chain.Config()), block.Transactions()); err != nil {
+			log.Error("nonce regression", "err", err)
+			return block, nil, err
+		}
+	}
+
+	if api.cfg.EnforceGreedyOrdering {
+		if err := checkGreedyOrdering(block.Transactions(), block.BaseFee()); err != nil {
+			log.Error("non-greedy transaction ordering", "err", err)
+			return block, nil, err
+		}
+	}
+
+	if err := checkBlockedContracts(block, api.cfg.BlockedContractAddresses); err != nil {
+		log.Error("blocked contract interaction", "err", err)
+		return block, nil, err
 	}
 
-	if params.Message.ParentHash != phase0.Hash32(block.ParentHash()) {
-		log.Error("incorrect ParentHash", "got", params.Message.ParentHash.String(), "expected", block.ParentHash().String())
-		return fmt.Errorf("incorrect ParentHash %s, expected %s", params.Message.ParentHash.String(), block.ParentHash().String())
+	if err := checkExtraData(block.Extra(), api.cfg.RequiredExtraDataPrefixes); err != nil {
+		log.Error("invalid extra data", "err", err)
+		return block, nil, err
 	}
 
-	if params.Message.BlockHash != phase0.Hash32(block.Hash()) {
-		log.Error("incorrect BlockHash", "got", params.Message.BlockHash.String(), "expected", block.Hash().String())
-		return fmt.Errorf("incorrect BlockHash %s, expected %s", params.Message.BlockHash.String(), block.Hash().String())
+	if err := checkBuilderCoinbase(api.cfg.BuilderCoinbaseRegistry, params.Message.BuilderPubkey, block.Coinbase()); err != nil {
+		log.Error("unexpected builder coinbase", "err", err)
+		return block, nil, err
 	}
 
-	if params.Message.GasLimit != block.GasLimit() {
-		log.Error("incorrect GasLimit", "got", params.Message.GasLimit, "expected", block.GasLimit())
-		return fmt.Errorf("incorrect GasLimit %d, expected %d", params.Message.GasLimit, block.GasLimit())
+	if err := applyTxFilters(block.Transactions(), api.cfg.TxFilters); err != nil {
+		log.Error("transaction rejected by filter", "err", err)
+		return block, nil, err
 	}
 
-	if params.Message.GasUsed != block.GasUsed() {
-		log.Error("incorrect GasUsed", "got", params.Message.GasUsed, "expected", block.GasUsed())
-		return fmt.Errorf("incorrect GasUsed %d, expected %d", params.Message.GasUsed, block.GasUsed())
+	if api.cfg.ForbidZeroAddressTransactions {
+		if err := checkZeroAddressTransactions(block.Transactions()); err != nil {
+			log.Error("zero address transaction", "err", err)
+			return block, nil, err
+		}
 	}
 
 	feeRecipient := common.BytesToAddress(params.Message.ProposerFeeRecipient[:])
+	// Payments by a coinbase transfer are indirect by definition.
+	indirectPayment := api.cfg.AllowIndirectPayment || api.cfg.ProfitMode == ProfitModeCoinbaseTransfer
+	if !indirectPayment && block.Coinbase() != feeRecipient {
+		err := ErrCoinbaseMismatch{Got: block.Coinbase(), Expected: feeRecipient}
+		log.Error("indirect payment not allowed", "err", err)
+		return block, nil, err
+	}
+	if err := api.checkFeeRecipientAllowed(feeRecipient); err != nil {
+		log.Error("fee recipient not allowed", "err", err)
+		return block, nil, err
+	}
+
 	expectedProfit := params.Message.Value.ToBig()
 
-	var vmconfig vm.Config
+	// The value the replay verifies the proposer payment against, and how.
+	replayProfit, useBalanceDiffProfit := expectedProfit, api.useBalanceDiffProfit
+	switch api.cfg.ProfitMode {
+	case ProfitModeBalanceDiff:
+		useBalanceDiffProfit = true
+	case ProfitModeFeesOnly:
+		if block.Coinbase() != feeRecipient {
+			err := fmt.Errorf("%w: fee recipient %s is not the coinbase %s", ErrProfitModeViolation, feeRecipient, block.Coinbase())
+			log.Error("invalid proposer payment", "err", err)
+			return block, nil, err
+		}
+		// The fees are verified after the replay.
+		replayProfit, useBalanceDiffProfit = new(big.Int), true
+	case ProfitModeCoinbaseTransfer:
+		if err := checkCoinbaseTransfer(block, types.LatestSigner(api.chain.Config()), feeRecipient, expectedProfit); err != nil {
+			log.Error("invalid proposer payment", "err", err)
+			return block, nil, err
+		}
+		useBalanceDiffProfit = false
+	}
+
+	if api.priceOracle != nil {
+		if err := api.checkProfitPrice(block, expectedProfit); err != nil {
+			log.Error("mispriced bid value", "err", err)
+			return block, nil, err
+		}
+	}
+
+	if api.profits != nil {
+		if err := checkProfitAnomaly(api.profits, api.cfg.AnomalyThresholdSigma, expectedProfit); err != nil {
+			log.Warn("anomalous bid value", "builder", params.Message.BuilderPubkey.String(), "err", err)
+			if api.cfg.RejectAnomalousProfit {
+				return block, nil, err
+			}
+		}
+	}
 
-	err = api.eth.BlockChain().ValidatePayload(block, feeRecipient, expectedProfit, params.RegisteredGasLimit, vmconfig, api.useBalanceDiffProfit)
+	vmconfig := params.VMConfigOverrides.vmConfig()
+	var tracer *logger.AccessListTracer = nil
+	if api.accessVerifier != nil {
+		if err := api.accessVerifier.isBlacklisted(block.Coinbase()); err != nil {
+			return block, nil, err
+		}
+		if err := api.accessVerifier.isBlacklisted(feeRecipient); err != nil {
+			return block, nil, err
+		}
+		if err := api.accessVerifier.verifyTransactions(types.LatestSigner(api.chain.Config()), block.Transactions()); err != nil {
+			return block, nil, err
+		}
+		isPostMerge := true // the call is PoS-native
+		timestamp := params.ExecutionPayload.Timestamp
+		precompiles := vm.ActivePrecompiles(api.chain.Config().Rules(new(big.Int).SetUint64(params.ExecutionPayload.BlockNumber), isPostMerge, timestamp))
+		tracer = logger.NewAccessListTracer(nil, common.Address{}, common.Address{}, precompiles)
+		addTracer(&vmconfig, tracer)
+	}
+
+	if len(params.ExtraEIPs) > 0 {
+		if !api.cfg.AllowCustomEIPs {
+			log.Error("custom EIPs not allowed", "eips", params.ExtraEIPs)
+			return block, nil, ErrCustomEIPsNotAllowed
+		}
+		for _, eip := range params.ExtraEIPs {
+			if !vm.ValidEip(eip) {
+				log.Error("unsupported EIP", "eip", eip)
+				return block, nil, newValidationError(CodeUnsupportedEIP, "unsupported EIP %d", eip)
+			}
+		}
+		vmconfig.ExtraEips = params.ExtraEIPs
+	}
+
+	var contractTracer *contractAccessTracer
+	if api.cfg.MaxUniqueContractsAccessed > 0 {
+		contractTracer = newContractAccessTracer(api.cfg.MaxUniqueContractsAccessed)
+		addTracer(&vmconfig, contractTracer)
+	}
+	var depthTracer *trieDepthTracer
+	if api.cfg.MaxStateTrieDepth > 0 {
+		parent := api.chain.GetHeaderByHash(block.ParentHash())
+		if parent == nil {
+			return block, nil, fmt.Errorf("%w %s", ErrUnknownParent, block.ParentHash())
+		}
+		statedb, err := api.chain.StateAt(parent.Root)
+		if err != nil {
+			return block, nil, err
+		}
+		depthTracer = newTrieDepthTracer(api.cfg.MaxStateTrieDepth, statedb)
+		addTracer(&vmconfig, depthTracer)
+	}
+	if simulation != nil {
+		addTracer(&vmconfig, simulation)
+	}
+
+	// The original block is kept for the response, only the replay sees the skipped transactions removed.
+	replayed, skipped := skipTransactions(block, api.cfg.SkipTransactionHashes)
+	var stopMeasuring func()
+	if api.diskIO != nil {
+		stopMeasuring = api.diskIO.measure(params.Message.Slot)
+	}
+	reportProgress(ctx, ValidationProgress{Stage: stageFieldChecksPassed})
+	reportProgress(ctx, ValidationProgress{Stage: stageEVMStarted})
+	replay := func(ctx context.Context) {
+		result, err = api.chain.ValidatePayloadWithResult(ctx, replayed, feeRecipient, replayProfit, params.RegisteredGasLimit, vmconfig, useBalanceDiffProfit, skipped)
+	}
+	if api.cfg.ProfilingMode {
+		pprof.Do(ctx, pprof.Labels("slot", strconv.FormatUint(params.Message.Slot, 10), "builder", params.Message.BuilderPubkey.String()), replay)
+	} else {
+		replay(ctx)
+	}
+	if stopMeasuring != nil {
+		stopMeasuring()
+	}
+	if contractTracer != nil && contractTracer.exceeded() {
+		err = fmt.Errorf("%w: more than %d", ErrTooManyContractAccesses, api.cfg.MaxUniqueContractsAccessed)
+		log.Error("too many contracts accessed", "err", err)
+		return block, nil, err
+	}
+	if depthTracer != nil && depthTracer.err != nil {
+		err = depthTracer.err
+		log.Error("excessive state trie depth", "err", err)
+		return block, nil, err
+	}
 	if err != nil {
 		log.Error("invalid payload", "hash", payload.BlockHash.String(), "number", payload.BlockNumber, "parentHash", payload.ParentHash.String(), "err", err)
-		return err
+		if api.cfg.TraceOnFailure && ctx.Err() == nil {
+			api.traceFailure(ctx, replayed, err)
+		}
+		if errors.Is(err, core.ErrInaccuratePayment) {
+			return block, nil, wrapValidationError(CodeInsufficientProfit, err)
+		}
+		return block, nil, err
+	}
+	reportProgress(ctx, ValidationProgress{Stage: stageEVMComplete, GasUsed: block.GasUsed()})
+
+	if api.cfg.ProfitMode == ProfitModeFeesOnly {
+		if err := checkPriorityFees(replayed, result.Receipts, expectedProfit); err != nil {
+			log.Error("invalid proposer payment", "err", err)
+			return block, nil, err
+		}
+	}
+	reportProgress(ctx, newProfitProgress(result.Profit))
+
+	skipped, err = api.verifyWithdrawalAmounts(ctx, params.Message, block)
+	if err != nil {
+		log.Error("invalid withdrawals", "err", err)
+		return block, nil, err
+	}
+	beaconSkipped = beaconSkipped || skipped
+
+	if err := checkMEVPolicy(api.cfg.MEVClassifier, api.cfg.BlockedMEVTypes, block, result.Receipts); err != nil {
+		log.Error("blocked MEV", "err", err)
+		return block, nil, err
+	}
+
+	if api.cfg.DetectGasPadding {
+		if err := api.checkGasPadding(block, api.cfg.EnforceNoPadding); err != nil {
+			log.Error("gas padding", "err", err)
+			return block, nil, err
+		}
+	}
+
+	if err := checkBlockedContractLogs(block, result.Receipts, api.cfg.BlockedContractAddresses); err != nil {
+		log.Error("blocked contract interaction", "err", err)
+		return block, nil, err
+	}
+
+	if api.cfg.VerifyLogOrdering {
+		if err := checkLogOrdering(result.Receipts); err != nil {
+			log.Error("invalid receipts", "err", err)
+			return block, nil, err
+		}
+	}
+
+	if api.cfg.DepositContractAddress != (common.Address{}) {
+		if err := checkDepositLogs(result.Receipts, api.cfg.DepositContractAddress); err != nil {
+			log.Error("invalid deposit", "err", err)
+			return block, nil, err
+		}
+	}
+
+	if api.accessVerifier != nil && tracer != nil {
+		if err := api.accessVerifier.verifyTraces(tracer); err != nil {
+			return block, nil, err
+		}
+	}
+
+	if api.cfg.EnableRoundTripCheck {
+		if err := checkPayloadRoundTrip(block, payload); err != nil {
+			log.Error("payload round trip mismatch", "err", err)
+			return block, nil, err
+		}
+	}
+
+	if api.beaconNode != nil {
+		if err := api.beaconNode.validate(ctx, &params.SubmitBlockRequest); err != nil {
+			log.Error("rejected by beacon node", "err", err)
+			return block, nil, err
+		}
+	}
+
+	if api.auditContract != nil {
+		api.auditContract.enqueue(block, result.Profit)
+	}
+
+	if api.nonces != nil {
+		api.nonces.record(params.Message.BuilderPubkey, params.Message.Slot, api.maxSlot(), types.LatestSigner(api.chain.Config()), block.Transactions())
+	}
+	if api.profits != nil {
+		api.profits.add(expectedProfit)
 	}
 
 	log.Info("validated block", "hash", block.Hash(), "number", block.NumberU64(), "parentHash", block.ParentHash())
+	return block, result, nil
+}
+
+// ValidationResponse is the outcome of a V2 validation as reported by ValidateBuilderSubmissionV2Details.
+type ValidationResponse struct {
+	Valid             bool   `json:"valid"`
+	Error             string `json:"error,omitempty"`
+	DurationMs        int64  `json:"duration_ms"`
+	GasUsed           uint64 `json:"gas_used"`
+	BaseFee           string `json:"base_fee"`
+	MeasuredProfitWei string `json:"measured_profit_wei"`
+	BlockHash         string `json:"block_hash"`
+}
+
+// ValidateBuilderSubmissionV2Details validates the submission like ValidateBuilderSubmissionV2 but
+// always returns the validation details, with any validation error embedded in the response.
+func (api *BlockValidationAPI) ValidateBuilderSubmissionV2Details(ctx context.Context, params *BuilderBlockValidationRequestV2) *ValidationResponse {
+	start := time.Now()
+	block, result, err := api.validateBuilderSubmissionV2(ctx, params, nil)
+
+	response := &ValidationResponse{
+		Valid:      err == nil,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		response.Error = err.Error()
+	}
+	if block != nil {
+		response.GasUsed = block.GasUsed()
+		response.BaseFee = block.BaseFee().String()
+		response.BlockHash = block.Hash().String()
+	}
+	if result != nil {
+		response.MeasuredProfitWei = result.FeeRecipientBalanceDelta.String()
+	}
+	return response
+}
+
+// ValidateBuilderSubmissionBatch validates the submissions concurrently, at most
+// MaxConcurrentValidations at a time. It returns the validation error of each submission in
+// request order, an empty string for valid submissions. A submission whose validation panics is
+// reported as failed without affecting the rest of the batch.
+func (api *BlockValidationAPI) ValidateBuilderSubmissionBatch(ctx context.Context, params []*BuilderBlockValidationRequestV2) []string {
+	var (
+		results = make([]string, len(params))
+		sem     = make(chan struct{}, api.maxConcurrentValidations())
+		wg      sync.WaitGroup
+	)
+	for i, req := range params {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, req *BuilderBlockValidationRequestV2) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Error("panic validating batched submission", "index", i, "err", r)
+					results[i] = fmt.Sprintf("panic validating submission: %v", r)
+				}
+				<-sem
+				wg.Done()
+			}()
+			if _, _, err := api.validateBuilderSubmissionV2(ctx, req, nil); err != nil {
+				results[i] = err.Error()
+			}
+		}(i, req)
+	}
+	wg.Wait()
+	return results
+}
+
+// checkParent verifies that the parent of the block is known and, unless AllowNonCanonicalParent
+// is set, canonical. Blocks are executed on the state of their parent either way.
+func (api *BlockValidationAPI) checkParent(block *types.Block) error {
+	parent := api.chain.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return fmt.Errorf("%w %s", ErrUnknownParent, block.ParentHash())
+	}
+	if !api.cfg.AllowNonCanonicalParent && api.chain.GetCanonicalHash(parent.Number.Uint64()) != parent.Hash() {
+		return fmt.Errorf("%w %s", ErrNonCanonicalParent, parent.Hash())
+	}
 	return nil
 }
+
+// checkRegisteredGasLimit verifies the gas limit of the block against the registered one before the
+// block is executed. Blocks with an unknown parent are left to payload validation.
+func (api *BlockValidationAPI) checkRegisteredGasLimit(block *types.Block, registeredGasLimit uint64) error {
+	parent := api.chain.GetHeaderByHash(block.ParentHash())
+	if parent == nil {
+		return nil
+	}
+	return checkRegisteredGasLimit(block.GasLimit(), registeredGasLimit, parent.GasLimit)
+}
+
+// checkMinProfit rejects bids claiming less than the minimum profit of their fee recipient.
+func (api *BlockValidationAPI) checkMinProfit(message *apiv1.BidTrace) error {
+	if message == nil {
+		return nil
+	}
+	feeRecipient := common.BytesToAddress(message.ProposerFeeRecipient[:])
+	min, ok := api.cfg.MinProfitByRecipient[feeRecipient]
+	if !ok {
+		min = api.cfg.MinProfit
+	}
+	if min == nil {
+		return nil
+	}
+	if value := message.Value.ToBig(); value.Cmp(min) < 0 {
+		return newValidationError(CodeInsufficientProfit, "value %s below the minimum profit %s of fee recipient %s", value, min, feeRecipient)
+	}
+	return nil
+}
+
+// checkFeeRecipientAllowed rejects fee recipients missing from AllowedFeeRecipients, if set.
+func (api *BlockValidationAPI) checkFeeRecipientAllowed(feeRecipient common.Address) error {
+	if api.allowedFeeRecipients == nil {
+		return nil
+	}
+	if _, ok := api.allowedFeeRecipients[feeRecipient]; !ok {
+		return fmt.Errorf("%w %s", ErrUnknownFeeRecipient, feeRecipient)
+	}
+	return nil
+}
+
+// checkPayloadTimestamp verifies the timestamp of a payload against its parent, the slot of the
+// bid and the wall clock if MaxTimestampDrift is set. Payloads with an unknown parent are left to
+// payload validation.
+func (api *BlockValidationAPI) checkPayloadTimestamp(message *apiv1.BidTrace, parentHash common.Hash, timestamp uint64) error {
+	if api.cfg.MaxTimestampDrift <= 0 {
+		return nil
+	}
+	parent := api.chain.GetHeaderByHash(parentHash)
+	if parent == nil {
+		return nil
+	}
+	var slot, genesisTime uint64
+	if message != nil {
+		slot, genesisTime = message.Slot, api.cfg.BeaconGenesisTime
+	}
+	return checkTimestamp(parent.Time, timestamp, slot, genesisTime, time.Now(), api.slotDuration(), api.cfg.MaxTimestampDrift)
+}
+
+func (api *BlockValidationAPI) slotDuration() time.Duration {
+	if api.cfg.SlotDuration > 0 {
+		return api.cfg.SlotDuration
+	}
+	return defaultSlotDuration
+}
+
+// maxSlot returns the highest slot submissions can be built for, the one after the slot of the
+// chain head. Slots are not bounded without BeaconGenesisTime.
+func (api *BlockValidationAPI) maxSlot() uint64 {
+	if api.cfg.BeaconGenesisTime == 0 || api.chain == nil {
+		return math.MaxUint64
+	}
+	head := api.chain.CurrentHeader()
+	if head.Time < api.cfg.BeaconGenesisTime {
+		return 1
+	}
+	return (head.Time-api.cfg.BeaconGenesisTime)/slotSeconds(api.slotDuration()) + 1
+}
+
+// withValidationTimeout derives the context of a single validation, with the configured timeout.
+func (api *BlockValidationAPI) withValidationTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if api.cfg.ValidationTimeout > 0 {
+		return context.WithTimeout(ctx, api.cfg.ValidationTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+func (api *BlockValidationAPI) maxConcurrentValidations() int {
+	if api.cfg.MaxConcurrentValidations > 0 {
+		return api.cfg.MaxConcurrentValidations
+	}
+	return runtime.NumCPU()
+}
+
+// ConfiguredChecks lists the validation checks enabled by the BlockValidationConfig. Settings
+// that may be sensitive, such as file paths and endpoints, are only reported as enabled or not.
+type ConfiguredChecks struct {
+	UseBalanceDiffProfit          bool       `json:"useBalanceDiffProfit"`
+	ProfitMode                    ProfitMode `json:"profitMode,omitempty"`
+	ProfitMultiplier              float64    `json:"profitMultiplier"`
+	BlacklistCheck                bool       `json:"blacklistCheck"`
+	RandaoCheck                   bool       `json:"randaoCheck"`
+	ProposerDutiesCheck           bool       `json:"proposerDutiesCheck"`
+	WithdrawalsRootCheck          bool       `json:"withdrawalsRootCheck"`
+	BlockedContractAddresses      int        `json:"blockedContractAddresses"`
+	DepositLogCheck               bool       `json:"depositLogCheck"`
+	AllowIndirectPayment          bool       `json:"allowIndirectPayment"`
+	WithdrawalAmountCheck         bool       `json:"withdrawalAmountCheck"`
+	MaxSubmissionsPerSlot         int        `json:"maxSubmissionsPerSlot"`
+	AutoBlockAfterFailures        int        `json:"autoBlockAfterFailures"`
+	AuditLog                      bool       `json:"auditLog"`
+	OTLPMetrics                   bool       `json:"otlpMetrics"`
+	BlockedMEVTypes               []string   `json:"blockedMEVTypes"`
+	AllowCustomEIPs               bool       `json:"allowCustomEIPs"`
+	VerifyLogOrdering             bool       `json:"verifyLogOrdering"`
+	EnforceGreedyOrdering         bool       `json:"enforceGreedyOrdering"`
+	RoundTripCheck                bool       `json:"roundTripCheck"`
+	GasPaddingCheck               bool       `json:"gasPaddingCheck"`
+	MaxUniqueContracts            int        `json:"maxUniqueContractsAccessed"`
+	PriceOracleCheck              bool       `json:"priceOracleCheck"`
+	MaxStateTrieDepth             int        `json:"maxStateTrieDepth"`
+	NonceMonotonicity             bool       `json:"nonceMonotonicity"`
+	EnforceNoPadding              bool       `json:"enforceNoPadding"`
+	MaxWithdrawalsPerBlock        int        `json:"maxWithdrawalsPerBlock"`
+	MinPrivateTxRatio             float64    `json:"minPrivateTxRatio"`
+	TrackDiskReads                bool       `json:"trackDiskReads"`
+	BuilderSignatureCheck         bool       `json:"builderSignatureCheck"`
+	AllowedFeeRecipients          int        `json:"allowedFeeRecipients"`
+	MinProfit                     *big.Int   `json:"minProfit"`
+	MinProfitByRecipient          int        `json:"minProfitByRecipient"`
+	TraceOnFailure                bool       `json:"traceOnFailure"`
+	RequiredExtraDataPrefixes     int        `json:"requiredExtraDataPrefixes"`
+	MaxTransactionsPerBlock       int        `json:"maxTransactionsPerBlock"`
+	AllowNonCanonicalParent       bool       `json:"allowNonCanonicalParent"`
+	MaxHeadAgeSeconds             int        `json:"maxHeadAgeSeconds"`
+	BuilderCoinbaseRegistry       int        `json:"builderCoinbaseRegistry"`
+	TxFilters                     int        `json:"txFilters"`
+	BeaconNodeValidation          bool       `json:"beaconNodeValidation"`
+	AllowVMConfigOverrides        bool       `json:"allowVMConfigOverrides"`
+	AnomalyThresholdSigma         float64    `json:"anomalyThresholdSigma"`
+	RejectAnomalousProfit         bool       `json:"rejectAnomalousProfit"`
+	ForbidZeroAddressTransactions bool       `json:"forbidZeroAddressTransactions"`
+}
+
+// ConfiguredChecks returns the validation checks enabled on this node.
+func (api *BlockValidationAPI) ConfiguredChecks() ConfiguredChecks {
+	cfg := api.cfg
+	beaconChecks := cfg.BeaconClient != nil
+	var blockedMEVTypes []string
+	if cfg.MEVClassifier != nil {
+		blockedMEVTypes = cfg.BlockedMEVTypes
+	}
+	return ConfiguredChecks{
+		UseBalanceDiffProfit:          api.useBalanceDiffProfit,
+		ProfitMode:                    cfg.ProfitMode,
+		ProfitMultiplier:              cfg.ProfitMultiplier,
+		BlacklistCheck:                api.accessVerifier != nil,
+		RandaoCheck:                   beaconChecks,
+		ProposerDutiesCheck:           beaconChecks,
+		WithdrawalsRootCheck:          beaconChecks,
+		BlockedContractAddresses:      len(cfg.BlockedContractAddresses),
+		DepositLogCheck:               cfg.DepositContractAddress != (common.Address{}),
+		AllowIndirectPayment:          cfg.AllowIndirectPayment,
+		WithdrawalAmountCheck:         cfg.WithdrawalsOracle != nil,
+		MaxSubmissionsPerSlot:         cfg.MaxSubmissionsPerSlot,
+		AutoBlockAfterFailures:        cfg.AutoBlockAfterFailures,
+		AuditLog:                      api.audit != nil,
+		OTLPMetrics:                   api.otlp != nil,
+		BlockedMEVTypes:               blockedMEVTypes,
+		AllowCustomEIPs:               cfg.AllowCustomEIPs,
+		VerifyLogOrdering:             cfg.VerifyLogOrdering,
+		EnforceGreedyOrdering:         cfg.EnforceGreedyOrdering,
+		RoundTripCheck:                cfg.EnableRoundTripCheck,
+		GasPaddingCheck:               cfg.DetectGasPadding,
+		MaxUniqueContracts:            cfg.MaxUniqueContractsAccessed,
+		PriceOracleCheck:              api.priceOracle != nil,
+		MaxStateTrieDepth:             cfg.MaxStateTrieDepth,
+		NonceMonotonicity:             cfg.EnforceNonceMonotonicity,
+		EnforceNoPadding:              cfg.DetectGasPadding && cfg.EnforceNoPadding,
+		MaxWithdrawalsPerBlock:        MaxWithdrawalsPerBlock,
+		MinPrivateTxRatio:             cfg.MinPrivateTxRatio,
+		TrackDiskReads:                api.diskIO != nil,
+		BuilderSignatureCheck:         cfg.VerifyBuilderSignature,
+		AllowedFeeRecipients:          len(cfg.AllowedFeeRecipients),
+		MinProfit:                     cfg.MinProfit,
+		MinProfitByRecipient:          len(cfg.MinProfitByRecipient),
+		TraceOnFailure:                cfg.TraceOnFailure,
+		RequiredExtraDataPrefixes:     len(cfg.RequiredExtraDataPrefixes),
+		MaxTransactionsPerBlock:       cfg.MaxTransactionsPerBlock,
+		AllowNonCanonicalParent:       cfg.AllowNonCanonicalParent,
+		MaxHeadAgeSeconds:             cfg.CircuitBreaker.MaxHeadAgeSeconds,
+		BuilderCoinbaseRegistry:       len(cfg.BuilderCoinbaseRegistry),
+		TxFilters:                     len(cfg.TxFilters),
+		BeaconNodeValidation:          cfg.BeaconNodeURL != "",
+		AllowVMConfigOverrides:        cfg.AllowVMConfigOverrides,
+		AnomalyThresholdSigma:         cfg.AnomalyThresholdSigma,
+		RejectAnomalousProfit:         cfg.RejectAnomalousProfit,
+		ForbidZeroAddressTransactions: cfg.ForbidZeroAddressTransactions,
+	}
+}
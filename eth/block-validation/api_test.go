@@ -1,10 +1,15 @@
 package blockvalidation
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"os"
+	"sync"
 	"testing"
 	"time"
 
@@ -36,6 +41,8 @@ import (
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
 
+	"github.com/flashbots/go-boost-utils/bls"
+	"github.com/flashbots/go-boost-utils/ssz"
 	boostTypes "github.com/flashbots/go-boost-utils/types"
 )
 
@@ -68,7 +75,8 @@ func TestValidateBuilderSubmissionV1(t *testing.T) {
 	ethservice.Merger().ReachTTD()
 	defer n.Close()
 
-	api := NewBlockValidationAPI(ethservice, true)
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
 	parent := preMergeBlocks[len(preMergeBlocks)-1]
 
 	api.eth.APIBackend.Miner().SetEtherbase(testValidatorAddr)
@@ -115,22 +123,22 @@ func TestValidateBuilderSubmissionV1(t *testing.T) {
 	}
 
 	blockRequest.Message.Value = uint256.NewInt(190526394825529)
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV1(blockRequest), "inaccurate payment")
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV1(context.Background(), blockRequest), "inaccurate payment")
 	blockRequest.Message.Value = uint256.NewInt(149830884438530)
-	require.NoError(t, api.ValidateBuilderSubmissionV1(blockRequest))
+	require.NoError(t, api.ValidateBuilderSubmissionV1(context.Background(), blockRequest))
 
 	blockRequest.Message.GasLimit += 1
 	blockRequest.ExecutionPayload.GasLimit += 1
 	updatePayloadHash(t, blockRequest)
 
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV1(blockRequest), "incorrect gas limit set")
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV1(context.Background(), blockRequest), "incorrect gas limit set")
 
 	blockRequest.Message.GasLimit -= 1
 	blockRequest.ExecutionPayload.GasLimit -= 1
 	updatePayloadHash(t, blockRequest)
 
 	blockRequest.Message.GasUsed = 10
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV1(blockRequest), "incorrect GasUsed 10, expected 119990")
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV1(context.Background(), blockRequest), "incorrect GasUsed 10, expected 119990")
 	blockRequest.Message.GasUsed = execData.GasUsed
 
 	newTestKey, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f290")
@@ -147,7 +155,10 @@ func TestValidateBuilderSubmissionV1(t *testing.T) {
 	copy(invalidPayload.ReceiptsRoot[:], hexutil.MustDecode("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")[:32])
 	blockRequest.ExecutionPayload = invalidPayload
 	updatePayloadHash(t, blockRequest)
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV1(blockRequest), "could not apply tx 4", "insufficient funds for gas * price + value")
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV1(context.Background(), blockRequest), "could not apply tx 4", "insufficient funds for gas * price + value")
+
+	blockRequest.ExecutionPayload.Transactions = nil
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV1(context.Background(), blockRequest), ErrNilTransactions)
 }
 
 func TestValidateBuilderSubmissionV2(t *testing.T) {
@@ -159,7 +170,8 @@ func TestValidateBuilderSubmissionV2(t *testing.T) {
 	ethservice.Merger().ReachTTD()
 	defer n.Close()
 
-	api := NewBlockValidationAPI(ethservice, nil, true)
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
 	parent := preMergeBlocks[len(preMergeBlocks)-1]
 
 	api.eth.APIBackend.Miner().SetEtherbase(testBuilderAddr)
@@ -226,15 +238,25 @@ func TestValidateBuilderSubmissionV2(t *testing.T) {
 		WithdrawalsRoot:    withdrawalsRoot,
 	}
 
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(blockRequest), "inaccurate payment")
+	err = api.ValidateBuilderSubmissionV2(context.Background(), blockRequest)
+	require.ErrorContains(t, err, "inaccurate payment")
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, CodeInsufficientProfit, validationErr.Code)
 	blockRequest.Message.Value = uint256.NewInt(149842511727212)
-	require.NoError(t, api.ValidateBuilderSubmissionV2(blockRequest))
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), blockRequest))
+
+	blockRequest.Message.GasUsed += 1
+	err = api.ValidateBuilderSubmissionV2(context.Background(), blockRequest)
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, CodeGasUsedMismatch, validationErr.Code)
+	blockRequest.Message.GasUsed -= 1
 
 	blockRequest.Message.GasLimit += 1
 	blockRequest.ExecutionPayload.GasLimit += 1
 	updatePayloadHashV2(t, blockRequest)
 
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(blockRequest), "incorrect gas limit set")
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(context.Background(), blockRequest), "incorrect gas limit set")
 
 	blockRequest.Message.GasLimit -= 1
 	blockRequest.ExecutionPayload.GasLimit -= 1
@@ -247,7 +269,7 @@ func TestValidateBuilderSubmissionV2(t *testing.T) {
 			testAddr: {},
 		},
 	}
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(blockRequest), "transaction from blacklisted address 0x71562b71999873DB5b286dF957af199Ec94617F7")
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(context.Background(), blockRequest), "transaction from blacklisted address 0x71562b71999873DB5b286dF957af199Ec94617F7")
 
 	// Test tx to blacklisted address
 	api.accessVerifier = &AccessVerifier{
@@ -255,12 +277,12 @@ func TestValidateBuilderSubmissionV2(t *testing.T) {
 			{0x16}: {},
 		},
 	}
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(blockRequest), "transaction to blacklisted address 0x1600000000000000000000000000000000000000")
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(context.Background(), blockRequest), "transaction to blacklisted address 0x1600000000000000000000000000000000000000")
 
 	api.accessVerifier = nil
 
 	blockRequest.Message.GasUsed = 10
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(blockRequest), "incorrect GasUsed 10, expected 119996")
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(context.Background(), blockRequest), "incorrect GasUsed 10, expected 119996")
 	blockRequest.Message.GasUsed = execData.GasUsed
 
 	newTestKey, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f290")
@@ -277,7 +299,110 @@ func TestValidateBuilderSubmissionV2(t *testing.T) {
 	copy(invalidPayload.ReceiptsRoot[:], hexutil.MustDecode("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")[:32])
 	blockRequest.ExecutionPayload = invalidPayload
 	updatePayloadHashV2(t, blockRequest)
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(blockRequest), "could not apply tx 4", "insufficient funds for gas * price + value")
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(context.Background(), blockRequest), "could not apply tx 4", "insufficient funds for gas * price + value")
+}
+
+func TestBlacklistLoad(t *testing.T) {
+	file, err := os.CreateTemp(".", "blacklist")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+
+	av, err := NewAccessVerifierFromFile(file.Name())
+	require.Error(t, err)
+	require.Nil(t, av)
+
+	ba := BlacklistedAddresses{common.Address{0x13}, common.Address{0x14}}
+	bytes, err := json.MarshalIndent(ba, "", " ")
+	require.NoError(t, err)
+	err = os.WriteFile(file.Name(), bytes, 0644)
+	require.NoError(t, err)
+
+	av, err = NewAccessVerifierFromFile(file.Name())
+	require.NoError(t, err)
+	require.NotNil(t, av)
+	require.EqualValues(t, av.blacklistedAddresses, map[common.Address]struct{}{
+		{0x13}: {},
+		{0x14}: {},
+	})
+
+	require.NoError(t, av.verifyTraces(logger.NewAccessListTracer(nil, common.Address{}, common.Address{}, nil)))
+
+	acl := types.AccessList{
+		types.AccessTuple{
+			Address: common.Address{0x14},
+		},
+	}
+	tracer := logger.NewAccessListTracer(acl, common.Address{}, common.Address{}, nil)
+
+	require.ErrorContains(t, av.verifyTraces(tracer), "blacklisted address 0x1400000000000000000000000000000000000000 in execution trace")
+
+	acl = types.AccessList{
+		types.AccessTuple{
+			Address: common.Address{0x15},
+		},
+	}
+	tracer = logger.NewAccessListTracer(acl, common.Address{}, common.Address{}, nil)
+	require.NoError(t, av.verifyTraces(tracer))
+}
+
+func TestExpectedBlockValue(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	defer n.Close()
+
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
+	value, err := api.ExpectedBlockValue(lastBlock.NumberU64() + 1)
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{ProfitMultiplier: 1.5})
+	baseFee := misc.CalcBaseFee(ethservice.BlockChain().Config(), lastBlock.Header())
+	expected := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(lastBlock.GasLimit()/params.DefaultElasticityMultiplier))
+	expected.Mul(expected, big.NewInt(3))
+	expected.Div(expected, big.NewInt(2))
+
+	value, err = api.ExpectedBlockValue(lastBlock.NumberU64() + 1)
+	require.NoError(t, err)
+	require.Equal(t, expected, value)
+
+	_, err = api.ExpectedBlockValue(lastBlock.NumberU64() + 2)
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestComputeWithdrawalsRoot(t *testing.T) {
+	makeWithdrawals := func(n int) []*types.Withdrawal {
+		withdrawals := make([]*types.Withdrawal, n)
+		for i := range withdrawals {
+			withdrawals[i] = &types.Withdrawal{
+				Index:     uint64(i),
+				Validator: uint64(1000 + i),
+				Address:   common.Address{byte(i + 1)},
+				Amount:    uint64(32e9 + i),
+			}
+		}
+		return withdrawals
+	}
+
+	// Reference roots were computed by inserting the rlp-encoded withdrawals
+	// into a full (non-stack) trie keyed by rlp(index).
+	tests := []struct {
+		count int
+		root  common.Hash
+	}{
+		{0, types.EmptyWithdrawalsHash},
+		{1, common.HexToHash("0xd8f916414584effab87154bba01e26737de3941fc3630f717ffa64de3eb4057c")},
+		{4, common.HexToHash("0x8fc86280a141220fb41a4f7cde8516f60c908b335591ef492c73ccb944ed91f4")},
+		{16, common.HexToHash("0x63de38502ff55b6b29fde10f88da9f1310124ca40801b7b1c1da32cacf0bad68")},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d withdrawals", tt.count), func(t *testing.T) {
+			require.Equal(t, tt.root, ComputeWithdrawalsRoot(makeWithdrawals(tt.count)))
+		})
+	}
+	require.Equal(t, types.EmptyWithdrawalsHash, ComputeWithdrawalsRoot(nil))
 }
 
 func updatePayloadHash(t *testing.T, blockRequest *BuilderBlockValidationRequest) {
@@ -293,13 +418,20 @@ func updatePayloadHashV2(t *testing.T, blockRequest *BuilderBlockValidationReque
 }
 
 func generatePreMergeChain(n int) (*core.Genesis, []*types.Block) {
+	return generatePreMergeChainWithGasLimit(n, 0)
+}
+
+func generatePreMergeChainWithGasLimit(n int, gasLimit uint64) (*core.Genesis, []*types.Block) {
 	db := rawdb.NewMemoryDatabase()
-	config := params.AllEthashProtocolChanges
+	// Copy the config so that fork times set by one test do not leak into others.
+	config := new(params.ChainConfig)
+	*config = *params.AllEthashProtocolChanges
 	genesis := &core.Genesis{
 		Config:     config,
 		Alloc:      core.GenesisAlloc{testAddr: {Balance: testBalance}, testValidatorAddr: {Balance: testBalance}, testBuilderAddr: {Balance: testBalance}},
 		ExtraData:  []byte("test genesis"),
 		Timestamp:  9000,
+		GasLimit:   gasLimit,
 		BaseFee:    big.NewInt(params.InitialBaseFee),
 		Difficulty: big.NewInt(0),
 	}
@@ -323,7 +455,7 @@ func generatePreMergeChain(n int) (*core.Genesis, []*types.Block) {
 }
 
 // startEthService creates a full node instance for testing.
-func startEthService(t *testing.T, genesis *core.Genesis, blocks []*types.Block) (*node.Node, *eth.Ethereum) {
+func startEthService(t testing.TB, genesis *core.Genesis, blocks []*types.Block) (*node.Node, *eth.Ethereum) {
 	t.Helper()
 
 	n, err := node.New(&node.Config{
@@ -473,6 +605,7 @@ type buildBlockArgs struct {
 	extraData     []byte
 	baseFeePerGas *big.Int
 	withdrawals   types.Withdrawals
+	vmConfig      vm.Config
 }
 
 func buildBlock(args buildBlockArgs, chain *core.BlockChain) (*engine.ExecutableData, error) {
@@ -499,10 +632,9 @@ func buildBlock(args buildBlockArgs, chain *core.BlockChain) (*engine.Executable
 
 	receipts := make([]*types.Receipt, 0, len(args.txs))
 	gasPool := core.GasPool(header.GasLimit)
-	vmConfig := vm.Config{}
 	for i, tx := range args.txs {
 		statedb.SetTxContext(tx.Hash(), i)
-		receipt, err := core.ApplyTransaction(chain.Config(), chain, &args.feeRecipient, &gasPool, statedb, header, tx, &header.GasUsed, vmConfig, nil)
+		receipt, err := core.ApplyTransaction(chain.Config(), chain, &args.feeRecipient, &gasPool, statedb, header, tx, &header.GasUsed, args.vmConfig, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -561,7 +693,8 @@ func TestValidateBuilderSubmissionV2_CoinbasePaymentDefault(t *testing.T) {
 	ethservice.Merger().ReachTTD()
 	defer n.Close()
 
-	api := NewBlockValidationAPI(ethservice, nil, true)
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
 
 	baseFee := misc.CalcBaseFee(ethservice.BlockChain().Config(), lastBlock.Header())
 	txs := make(types.Transactions, 0)
@@ -641,21 +774,21 @@ func TestValidateBuilderSubmissionV2_CoinbasePaymentDefault(t *testing.T) {
 
 	req, err := executableDataToBlockValidationRequest(execData, testValidatorAddr, value, withdrawalsRoot)
 	require.NoError(t, err)
-	require.NoError(t, api.ValidateBuilderSubmissionV2(req))
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
 
 	// try to claim less profit than expected, should work
 	value.SetUint64(expectedProfit - 1)
 
 	req, err = executableDataToBlockValidationRequest(execData, testValidatorAddr, value, withdrawalsRoot)
 	require.NoError(t, err)
-	require.NoError(t, api.ValidateBuilderSubmissionV2(req))
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
 
 	// try to claim more profit than expected, should fail
 	value.SetUint64(expectedProfit + 1)
 
 	req, err = executableDataToBlockValidationRequest(execData, testValidatorAddr, value, withdrawalsRoot)
 	require.NoError(t, err)
-	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(req), "payment")
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(context.Background(), req), "payment")
 }
 
 func TestValidateBuilderSubmissionV2_Blocklist(t *testing.T) {
@@ -673,8 +806,10 @@ func TestValidateBuilderSubmissionV2_Blocklist(t *testing.T) {
 		},
 	}
 
-	apiWithBlock := NewBlockValidationAPI(ethservice, accessVerifier, true)
-	apiNoBlock := NewBlockValidationAPI(ethservice, nil, true)
+	apiWithBlock, err := NewBlockValidationAPI(ethservice, accessVerifier, true, nil)
+	require.NoError(t, err)
+	apiNoBlock, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
 
 	baseFee := misc.CalcBaseFee(ethservice.BlockChain().Config(), lastBlock.Header())
 	blockedTxs := make(types.Transactions, 0)
@@ -715,8 +850,1368 @@ func TestValidateBuilderSubmissionV2_Blocklist(t *testing.T) {
 			req, err := executableDataToBlockValidationRequest(execData, testValidatorAddr, common.Big0, withdrawalsRoot)
 			require.NoError(t, err)
 
-			require.NoError(t, apiNoBlock.ValidateBuilderSubmissionV2(req))
-			require.ErrorContains(t, apiWithBlock.ValidateBuilderSubmissionV2(req), "blacklisted")
+			require.NoError(t, apiNoBlock.ValidateBuilderSubmissionV2(context.Background(), req))
+			require.ErrorContains(t, apiWithBlock.ValidateBuilderSubmissionV2(context.Background(), req), "blacklisted")
+		})
+	}
+}
+
+func BenchmarkValidateBuilderSubmissionV2_BlockSize(b *testing.B) {
+	genesis, preMergeBlocks := generatePreMergeChainWithGasLimit(20, 30_000_000)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(b, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(b, err)
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	signer := types.LatestSigner(bc.Config())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	nonce := statedb.GetNonce(testAddr)
+	withdrawalsRoot := ComputeWithdrawalsRoot(nil)
+
+	for _, size := range []int{50, 200, 500, 1000} {
+		txs := make(types.Transactions, size)
+		for i := range txs {
+			txs[i] = types.MustSignNewTx(testKey, signer, &types.DynamicFeeTx{
+				ChainID:   bc.Config().ChainID,
+				Nonce:     nonce + uint64(i),
+				GasTipCap: big.NewInt(1),
+				GasFeeCap: new(big.Int).Mul(baseFee, big.NewInt(2)),
+				Gas:       params.TxGas,
+				To:        &common.Address{0x16},
+				Value:     big.NewInt(1),
+			})
+		}
+
+		execData, err := buildBlock(buildBlockArgs{
+			parentHash:    lastBlock.Hash(),
+			parentRoot:    lastBlock.Root(),
+			feeRecipient:  testValidatorAddr,
+			txs:           txs,
+			number:        lastBlock.NumberU64() + 1,
+			gasLimit:      lastBlock.GasLimit(),
+			timestamp:     lastBlock.Time() + 5,
+			baseFeePerGas: baseFee,
+		}, bc)
+		require.NoError(b, err)
+
+		req, err := executableDataToBlockValidationRequest(execData, testValidatorAddr, common.Big0, withdrawalsRoot)
+		require.NoError(b, err)
+
+		b.Run(fmt.Sprintf("txs=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := api.ValidateBuilderSubmissionV2(context.Background(), req); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateBuilderSubmissionV2_MaxWithdrawals(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
+	baseFee := misc.CalcBaseFee(ethservice.BlockChain().Config(), lastBlock.Header())
+
+	makeRequest := func(count int) *BuilderBlockValidationRequestV2 {
+		withdrawals := make([]*types.Withdrawal, count)
+		for i := range withdrawals {
+			withdrawals[i] = &types.Withdrawal{
+				Index:     uint64(i),
+				Validator: uint64(i),
+				Amount:    100,
+				Address:   testAddr,
+			}
+		}
+
+		execData, err := buildBlock(buildBlockArgs{
+			parentHash:    lastBlock.Hash(),
+			parentRoot:    lastBlock.Root(),
+			feeRecipient:  testValidatorAddr,
+			number:        lastBlock.NumberU64() + 1,
+			gasLimit:      lastBlock.GasLimit(),
+			timestamp:     lastBlock.Time() + 5,
+			baseFeePerGas: baseFee,
+			withdrawals:   withdrawals,
+		}, ethservice.BlockChain())
+		require.NoError(t, err)
+
+		req, err := executableDataToBlockValidationRequest(execData, testValidatorAddr, common.Big0, ComputeWithdrawalsRoot(withdrawals))
+		require.NoError(t, err)
+		return req
+	}
+
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), makeRequest(MaxWithdrawalsPerBlock)))
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), makeRequest(MaxWithdrawalsPerBlock+1)), ErrTooManyWithdrawals)
+}
+
+// buildTestRequestV2 builds a block paying the fees to testValidatorAddr on top of parent
+// and wraps it in a V2 validation request claiming the given value.
+func buildTestRequestV2(t testing.TB, chain *core.BlockChain, parent *types.Block, txs types.Transactions, withdrawals types.Withdrawals, value *big.Int) *BuilderBlockValidationRequestV2 {
+	t.Helper()
+	execData, err := buildBlock(buildBlockArgs{
+		parentHash:    parent.Hash(),
+		parentRoot:    parent.Root(),
+		feeRecipient:  testValidatorAddr,
+		txs:           txs,
+		number:        parent.NumberU64() + 1,
+		gasLimit:      parent.GasLimit(),
+		timestamp:     parent.Time() + 5,
+		baseFeePerGas: misc.CalcBaseFee(chain.Config(), parent.Header()),
+		withdrawals:   withdrawals,
+	}, chain)
+	require.NoError(t, err)
+
+	req, err := executableDataToBlockValidationRequest(execData, testValidatorAddr, value, ComputeWithdrawalsRoot(withdrawals))
+	require.NoError(t, err)
+	return req
+}
+
+func TestValidateBuilderSubmissionV2Details(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	signer := types.LatestSigner(bc.Config())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), signer, testKey)
+	profit := big.NewInt(21000 * baseFee.Int64())
+
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, profit)
+	response := api.ValidateBuilderSubmissionV2Details(context.Background(), req)
+	require.True(t, response.Valid)
+	require.Empty(t, response.Error)
+	require.EqualValues(t, 21000, response.GasUsed)
+	require.Equal(t, baseFee.String(), response.BaseFee)
+	require.Equal(t, profit.String(), response.MeasuredProfitWei)
+	require.Equal(t, req.Message.BlockHash.String(), response.BlockHash)
+
+	req = buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, new(big.Int).Add(profit, common.Big1))
+	response = api.ValidateBuilderSubmissionV2Details(context.Background(), req)
+	require.False(t, response.Valid)
+	require.Contains(t, response.Error, "payment")
+	require.Equal(t, req.Message.BlockHash.String(), response.BlockHash)
+	require.Empty(t, response.MeasuredProfitWei)
+
+	req.ExecutionPayload = nil
+	response = api.ValidateBuilderSubmissionV2Details(context.Background(), req)
+	require.False(t, response.Valid)
+	require.Equal(t, "nil execution payload", response.Error)
+}
+
+func TestValidateBuilderSubmissionV2_MergeNotActivated(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	genesis.Config.TerminalTotalDifficulty = new(big.Int).Add(genesis.Config.TerminalTotalDifficulty, big.NewInt(1_000_000_000))
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	defer n.Close()
+
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
+	req := buildTestRequestV2(t, ethservice.BlockChain(), lastBlock, nil, nil, common.Big0)
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrMergeNotActivated)
+}
+
+func TestValidateBuilderSubmissionV2_Hooks(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	var (
+		calls    []string
+		outcomes []ValidationOutcome
+		errs     []error
+		reject   error
+	)
+	recorder := func(name string) ValidationHooks {
+		return ValidationHooks{
+			PreValidation: func(req interface{}) error {
+				calls = append(calls, name+":pre")
+				return reject
+			},
+			PostValidation: func(req interface{}, outcome ValidationOutcome) {
+				calls = append(calls, name+":post")
+				outcomes = append(outcomes, outcome)
+			},
+			OnError: func(req interface{}, err error) {
+				calls = append(calls, name+":error")
+				errs = append(errs, err)
+			},
+		}
+	}
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{
+		UseBalanceDiffProfit: true,
+		Hooks:                CompositeHooks(recorder("a"), ValidationHooks{}, recorder("b")),
+	})
+
+	req := buildTestRequestV2(t, ethservice.BlockChain(), lastBlock, nil, nil, common.Big0)
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.Equal(t, []string{"a:pre", "b:pre", "a:post", "b:post"}, calls)
+	require.Len(t, outcomes, 2)
+	require.Equal(t, req.Message.BlockHash[:], outcomes[0].Block.Hash().Bytes())
+	require.NotNil(t, outcomes[0].Result)
+
+	calls = nil
+	req.Message.GasUsed++
+	err := api.ValidateBuilderSubmissionV2(context.Background(), req)
+	require.ErrorContains(t, err, "incorrect GasUsed")
+	require.Equal(t, []string{"a:pre", "b:pre", "a:error", "b:error"}, calls)
+	require.Equal(t, []error{err, err}, errs)
+
+	// A rejecting pre-validation hook stops the remaining ones and the validation itself.
+	calls, errs = nil, nil
+	reject = errors.New("rejected")
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), reject)
+	require.Equal(t, []string{"a:pre", "a:error", "b:error"}, calls)
+}
+
+func TestValidateBuilderSubmissionV2_CoinbaseMismatch(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	payment, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), testValidatorAddr, big.NewInt(10), 21000, baseFee, nil), types.LatestSigner(bc.Config()), testKey)
+
+	// The builder keeps the coinbase and pays the proposer with a transaction.
+	execData, err := buildBlock(buildBlockArgs{
+		parentHash:    lastBlock.Hash(),
+		parentRoot:    lastBlock.Root(),
+		feeRecipient:  testBuilderAddr,
+		txs:           types.Transactions{payment},
+		number:        lastBlock.NumberU64() + 1,
+		gasLimit:      lastBlock.GasLimit(),
+		timestamp:     lastBlock.Time() + 5,
+		baseFeePerGas: baseFee,
+	}, bc)
+	require.NoError(t, err)
+	req, err := executableDataToBlockValidationRequest(execData, testValidatorAddr, big.NewInt(10), ComputeWithdrawalsRoot(nil))
+	require.NoError(t, err)
+
+	strictAPI := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true})
+	err = strictAPI.ValidateBuilderSubmissionV2(context.Background(), req)
+	var mismatchErr ErrCoinbaseMismatch
+	require.True(t, errors.As(err, &mismatchErr))
+	require.Equal(t, ErrCoinbaseMismatch{Got: testBuilderAddr, Expected: testValidatorAddr}, mismatchErr)
+
+	indirectAPI := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true})
+	require.NoError(t, indirectAPI.ValidateBuilderSubmissionV2(context.Background(), req))
+}
+
+func TestConfiguredChecks(t *testing.T) {
+	api, err := NewBlockValidationAPI(nil, nil, true, nil)
+	require.NoError(t, err)
+	require.Equal(t, ConfiguredChecks{
+		UseBalanceDiffProfit:   true,
+		AllowIndirectPayment:   true,
+		MaxWithdrawalsPerBlock: MaxWithdrawalsPerBlock,
+	}, api.ConfiguredChecks())
+
+	api = newBlockValidationAPI(nil, &AccessVerifier{}, BlockValidationConfig{
+		ProfitMultiplier:         1.5,
+		BeaconClient:             &testBeaconClient{},
+		BlockedContractAddresses: []common.Address{{0x01}, {0x02}},
+		DepositContractAddress:   common.Address{0x03},
+		WithdrawalsOracle:        &testWithdrawalsOracle{},
+		MaxSubmissionsPerSlot:    10,
+		AutoBlockAfterFailures:   5,
+		OTLPEndpoint:             "http://localhost:4318",
+		MEVClassifier:            testMEVClassifier{},
+		BlockedMEVTypes:          []string{"sandwich"},
+		VerifyLogOrdering:        true,
+		EnforceGreedyOrdering:    true,
+		EnableRoundTripCheck:     true,
+	})
+	checks := api.ConfiguredChecks()
+	require.Equal(t, ConfiguredChecks{
+		ProfitMultiplier:         1.5,
+		BlacklistCheck:           true,
+		RandaoCheck:              true,
+		ProposerDutiesCheck:      true,
+		WithdrawalsRootCheck:     true,
+		BlockedContractAddresses: 2,
+		DepositLogCheck:          true,
+		WithdrawalAmountCheck:    true,
+		MaxSubmissionsPerSlot:    10,
+		AutoBlockAfterFailures:   5,
+		OTLPMetrics:              true,
+		BlockedMEVTypes:          []string{"sandwich"},
+		VerifyLogOrdering:        true,
+		EnforceGreedyOrdering:    true,
+		RoundTripCheck:           true,
+		MaxWithdrawalsPerBlock:   MaxWithdrawalsPerBlock,
+	}, checks)
+
+	// Endpoints are not exposed.
+	encoded, err := json.Marshal(checks)
+	require.NoError(t, err)
+	require.NotContains(t, string(encoded), "localhost")
+}
+
+func TestConcurrentValidationV2(t *testing.T) {
+	t.Parallel()
+
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	signer := types.LatestSigner(bc.Config())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	nonce := statedb.GetNonce(testAddr)
+	profit := big.NewInt(21000 * baseFee.Int64())
+
+	// Every request carries a different block, every other one claims too much profit.
+	const validations = 50
+	requests := make([]*BuilderBlockValidationRequestV2, validations)
+	for i := range requests {
+		tx, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x16}, big.NewInt(int64(i+1)), 21000, big.NewInt(2*baseFee.Int64()), nil), signer, testKey)
+		value := profit
+		if i%2 == 1 {
+			value = new(big.Int).Add(profit, common.Big1)
+		}
+		requests[i] = buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, value)
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, validations)
+	for i := range requests {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = api.ValidateBuilderSubmissionV2(context.Background(), requests[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if i%2 == 0 {
+			require.NoError(t, err, "request %d", i)
+		} else {
+			require.ErrorContains(t, err, "payment", "request %d", i)
+		}
+	}
+}
+
+func TestValidateBuilderSubmissionV2_SkipTransactionHashes(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	req := buildTestRequestV2(t, bc, lastBlock, nil, nil, common.Big0)
+
+	// A transaction from an unfunded account can not be replayed.
+	unfundedKey, _ := crypto.GenerateKey()
+	badTx, err := types.SignTx(types.NewTransaction(0, common.Address{0x16}, big.NewInt(1), 21000, baseFee, nil), types.LatestSigner(bc.Config()), unfundedKey)
+	require.NoError(t, err)
+	badTxData, err := badTx.MarshalBinary()
+	require.NoError(t, err)
+	req.ExecutionPayload.Transactions = append(req.ExecutionPayload.Transactions, badTxData)
+	updatePayloadHashV2(t, req)
+
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(context.Background(), req), "insufficient funds")
+
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{
+		UseBalanceDiffProfit:  true,
+		SkipTransactionHashes: []common.Hash{{0x01}, badTx.Hash()},
+	})
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.Equal(t, req.Message.BlockHash.String(), api.ValidateBuilderSubmissionV2Details(context.Background(), req).BlockHash)
+}
+
+func TestValidateBuilderSubmissionV2_ExtraEIPs(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	req := buildTestRequestV2(t, ethservice.BlockChain(), lastBlock, nil, nil, common.Big0)
+	req.ExtraEIPs = []int{3855}
+
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrCustomEIPsNotAllowed)
+
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, AllowCustomEIPs: true})
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	req.ExtraEIPs = []int{3855, 1}
+	err = api.ValidateBuilderSubmissionV2(context.Background(), req)
+	require.ErrorContains(t, err, "unsupported EIP 1")
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, CodeUnsupportedEIP, validationErr.Code)
+
+	// The field is decoded alongside the submission.
+	encoded, err := json.Marshal(&req.SubmitBlockRequest)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	fields["extra_eips"] = json.RawMessage("[3855,1]")
+	encoded, err = json.Marshal(fields)
+	require.NoError(t, err)
+	var decoded BuilderBlockValidationRequestV2
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Equal(t, []int{3855, 1}, decoded.ExtraEIPs)
+}
+
+func TestCheckGenesisHash(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	defer n.Close()
+	bc := ethservice.BlockChain()
+
+	require.NoError(t, checkGenesisHash(bc, bc.Genesis().Hash()))
+	require.ErrorIs(t, checkGenesisHash(bc, common.Hash{0x01}), ErrGenesisHashMismatch)
+	require.ErrorIs(t, Register(n, ethservice, BlockValidationConfig{ExpectedGenesisHash: common.Hash{0x01}}), ErrGenesisHashMismatch)
+}
+
+func TestCheckDifficulty(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	defer n.Close()
+	bc := ethservice.BlockChain()
+
+	child := func(difficulty *big.Int) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{
+			ParentHash: lastBlock.Hash(),
+			Number:     new(big.Int).Add(lastBlock.Number(), common.Big1),
+			Time:       lastBlock.Time() + 5,
+			Difficulty: difficulty,
+		})
+	}
+
+	// Before the terminal total difficulty the ethash difficulty applies.
+	tdGenesis := *genesis
+	tdConfig := *genesis.Config
+	tdConfig.TerminalTotalDifficulty = new(big.Int).Add(genesis.Config.TerminalTotalDifficulty, big.NewInt(1_000_000_000))
+	tdGenesis.Config = &tdConfig
+	preMergeNode, preMergeService := startEthService(t, &tdGenesis, preMergeBlocks)
+	defer preMergeNode.Close()
+	preMergeChain := preMergeService.BlockChain()
+	ethashDifficulty := preMergeChain.Engine().CalcDifficulty(preMergeChain, lastBlock.Time()+5, lastBlock.Header())
+	require.NotZero(t, ethashDifficulty.Sign())
+	require.NoError(t, checkDifficulty(preMergeChain, child(ethashDifficulty)))
+	var difficultyErr ErrDifficultyMismatch
+	require.True(t, errors.As(checkDifficulty(preMergeChain, child(common.Big0)), &difficultyErr))
+	require.Equal(t, ethashDifficulty, difficultyErr.Expected)
+
+	// After the merge the difficulty must be zero.
+	require.NoError(t, checkDifficulty(bc, child(common.Big0)))
+	require.True(t, errors.As(checkDifficulty(bc, child(common.Big1)), &difficultyErr))
+	require.Zero(t, difficultyErr.Expected.Sign())
+	require.Equal(t, common.Big1, difficultyErr.Got)
+
+	// Unknown parents are left to payload validation.
+	orphan := types.NewBlockWithHeader(&types.Header{ParentHash: common.Hash{0x01}, Number: big.NewInt(100), Difficulty: common.Big1})
+	require.NoError(t, checkDifficulty(bc, orphan))
+}
+
+func TestCheckBaseFee(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	defer n.Close()
+	bc := ethservice.BlockChain()
+
+	child := func(baseFee *big.Int) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{
+			ParentHash: lastBlock.Hash(),
+			Number:     new(big.Int).Add(lastBlock.Number(), common.Big1),
+			Time:       lastBlock.Time() + 5,
+			BaseFee:    baseFee,
 		})
 	}
+
+	expected := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	require.NoError(t, checkBaseFee(bc, child(expected)))
+	for _, baseFee := range []*big.Int{new(big.Int).Sub(expected, common.Big1), new(big.Int).Add(expected, common.Big1), nil} {
+		var verr *ValidationError
+		require.ErrorAs(t, checkBaseFee(bc, child(baseFee)), &verr)
+		require.Equal(t, CodeBaseFeeMismatch, verr.Code)
+	}
+
+	// Unknown parents are left to payload validation.
+	orphan := types.NewBlockWithHeader(&types.Header{ParentHash: common.Hash{0x01}, Number: big.NewInt(100), BaseFee: common.Big1})
+	require.NoError(t, checkBaseFee(bc, orphan))
+
+	// Blocks before London have no base fee.
+	preLondonGenesis := *genesis
+	preLondonConfig := *genesis.Config
+	preLondonConfig.LondonBlock, preLondonConfig.ArrowGlacierBlock, preLondonConfig.GrayGlacierBlock = nil, nil, nil
+	preLondonGenesis.Config = &preLondonConfig
+	preLondonNode, preLondonService := startEthService(t, &preLondonGenesis, nil)
+	defer preLondonNode.Close()
+	preLondonChain := preLondonService.BlockChain()
+	require.NoError(t, checkBaseFee(preLondonChain, types.NewBlockWithHeader(&types.Header{ParentHash: preLondonChain.Genesis().Hash(), Number: common.Big1})))
+}
+
+func buildTestRequestV1(t testing.TB, chain *core.BlockChain, parent *types.Block, txs types.Transactions, value *big.Int) *BuilderBlockValidationRequest {
+	t.Helper()
+	execData, err := buildBlock(buildBlockArgs{
+		parentHash:    parent.Hash(),
+		parentRoot:    parent.Root(),
+		feeRecipient:  testValidatorAddr,
+		txs:           txs,
+		number:        parent.NumberU64() + 1,
+		gasLimit:      parent.GasLimit(),
+		timestamp:     parent.Time() + 5,
+		baseFeePerGas: misc.CalcBaseFee(chain.Config(), parent.Header()),
+	}, chain)
+	require.NoError(t, err)
+	payload, err := ExecutableDataToExecutionPayload(execData)
+	require.NoError(t, err)
+
+	return &BuilderBlockValidationRequest{
+		SubmitBlockRequest: bellatrixapi.SubmitBlockRequest{
+			Message: &apiv1.BidTrace{
+				ParentHash:           phase0.Hash32(execData.ParentHash),
+				BlockHash:            phase0.Hash32(execData.BlockHash),
+				ProposerFeeRecipient: bellatrix.ExecutionAddress(testValidatorAddr),
+				GasLimit:             execData.GasLimit,
+				GasUsed:              execData.GasUsed,
+				Value:                uint256.MustFromBig(value),
+			},
+			ExecutionPayload: payload,
+		},
+		RegisteredGasLimit: execData.GasLimit,
+	}
+}
+
+// TestValidateBuilderSubmissionV1_vs_V2_Equivalence validates the same transactions without
+// withdrawals through V1 on a Bellatrix chain and V2 on a Capella chain. The checks both
+// versions share must agree on the outcome and the error. Transactions are plain transfers,
+// as the gas of contract creations differs between the forks.
+func TestValidateBuilderSubmissionV1_vs_V2_Equivalence(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	bellatrixNode, bellatrixService := startEthService(t, genesis, preMergeBlocks)
+	bellatrixService.Merger().ReachTTD()
+	defer bellatrixNode.Close()
+
+	capellaGenesis := *genesis
+	capellaConfig := *genesis.Config
+	shanghaiTime := lastBlock.Time() + 5
+	capellaConfig.ShanghaiTime = &shanghaiTime
+	capellaGenesis.Config = &capellaConfig
+	capellaNode, capellaService := startEthService(t, &capellaGenesis, preMergeBlocks)
+	capellaService.Merger().ReachTTD()
+	defer capellaNode.Close()
+
+	apiV1, err := NewBlockValidationAPI(bellatrixService, nil, true, nil)
+	require.NoError(t, err)
+	apiV2, err := NewBlockValidationAPI(capellaService, nil, true, nil)
+	require.NoError(t, err)
+
+	baseFee := misc.CalcBaseFee(genesis.Config, lastBlock.Header())
+	signer := types.LatestSigner(genesis.Config)
+	statedb, _ := bellatrixService.BlockChain().StateAt(lastBlock.Root())
+	nonce := statedb.GetNonce(testAddr)
+	tx1, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), signer, testKey)
+	tx2, _ := types.SignTx(types.NewTransaction(nonce+1, testAddr, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), signer, testKey)
+	txs := types.Transactions{tx1, tx2}
+	profit := big.NewInt(2 * 21000 * baseFee.Int64())
+
+	tests := []struct {
+		name   string
+		value  *big.Int
+		mutate func(msg *apiv1.BidTrace)
+		valid  bool
+	}{
+		{name: "valid", value: profit, valid: true},
+		{name: "underclaimed profit", value: new(big.Int).Sub(profit, common.Big1), valid: true},
+		{name: "overclaimed profit", value: new(big.Int).Add(profit, common.Big1)},
+		{name: "parent hash", value: profit, mutate: func(msg *apiv1.BidTrace) { msg.ParentHash = phase0.Hash32{0x01} }},
+		{name: "gas limit", value: profit, mutate: func(msg *apiv1.BidTrace) { msg.GasLimit++ }},
+		{name: "gas used", value: profit, mutate: func(msg *apiv1.BidTrace) { msg.GasUsed-- }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqV1 := buildTestRequestV1(t, bellatrixService.BlockChain(), lastBlock, txs, tt.value)
+			reqV2 := buildTestRequestV2(t, capellaService.BlockChain(), lastBlock, txs, nil, tt.value)
+			if tt.mutate != nil {
+				tt.mutate(reqV1.Message)
+				tt.mutate(reqV2.Message)
+			}
+
+			errV1 := apiV1.ValidateBuilderSubmissionV1(context.Background(), reqV1)
+			errV2 := apiV2.ValidateBuilderSubmissionV2(context.Background(), reqV2)
+			if tt.valid {
+				require.NoError(t, errV1)
+				require.NoError(t, errV2)
+				return
+			}
+			require.Error(t, errV1)
+			require.Error(t, errV2)
+			require.Equal(t, errV1.Error(), errV2.Error())
+		})
+	}
+}
+
+func TestValidateBuilderSubmissionV2_GasPadding(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	signer := types.LatestSigner(bc.Config())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	nonce := statedb.GetNonce(testAddr)
+	exact, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), signer, testKey)
+	padded, _ := types.SignTx(types.NewTransaction(nonce+1, common.Address{0x16}, big.NewInt(10), 30000, big.NewInt(2*baseFee.Int64()), nil), signer, testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{exact, padded}, nil, big.NewInt(2*21000*baseFee.Int64()))
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, DetectGasPadding: true})
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, DetectGasPadding: true, EnforceNoPadding: true})
+	err := api.ValidateBuilderSubmissionV2(context.Background(), req)
+	var paddingErr ErrGasPadding
+	require.True(t, errors.As(err, &paddingErr))
+	require.Equal(t, padded.Hash(), paddingErr.TxHash)
+	require.Equal(t, uint64(21000), paddingErr.Estimate)
+}
+
+func TestValidateBuilderSubmissionV2_MaxUniqueContractsAccessed(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	gasPrice := big.NewInt(2 * baseFee.Int64())
+	signer := types.LatestSigner(bc.Config())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	nonce := statedb.GetNonce(testAddr)
+
+	// Two contracts are deployed and called, in total three calls to contracts are made.
+	var txs types.Transactions
+	for i := uint64(0); i < 2; i++ {
+		create, _ := types.SignTx(types.NewContractCreation(nonce+2*i, common.Big0, 1000000, gasPrice, logCode), signer, testKey)
+		contract := crypto.CreateAddress(testAddr, nonce+2*i)
+		call, _ := types.SignTx(types.NewTransaction(nonce+2*i+1, contract, common.Big0, 100000, gasPrice, nil), signer, testKey)
+		txs = append(txs, create, call)
+	}
+	repeat, _ := types.SignTx(types.NewTransaction(nonce+4, crypto.CreateAddress(testAddr, nonce), common.Big0, 100000, gasPrice, nil), signer, testKey)
+	txs = append(txs, repeat)
+	req := buildTestRequestV2(t, bc, lastBlock, txs, nil, common.Big0)
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, MaxUniqueContractsAccessed: 2})
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, MaxUniqueContractsAccessed: 1})
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrTooManyContractAccesses)
+
+	// The limit also applies together with the blacklist tracer.
+	api = newBlockValidationAPI(ethservice, &AccessVerifier{}, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, MaxUniqueContractsAccessed: 1})
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrTooManyContractAccesses)
+}
+
+func TestValidateBuilderSubmissionV2_MaxStateTrieDepth(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	proof, err := statedb.GetProof(testAddr)
+	require.NoError(t, err)
+	depth := len(proof)
+	require.Greater(t, depth, 1)
+
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, MaxStateTrieDepth: depth})
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, MaxStateTrieDepth: depth - 1})
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrExcessiveTrieDepth)
+}
+
+func TestValidateBuilderSubmissionV2_ContextCanceled(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(ctx, req), context.Canceled)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(ctx, req), context.DeadlineExceeded)
+
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+}
+
+func TestValidateBuilderSubmissionV2_MinPrivateTxRatio(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, MinPrivateTxRatio: 1})
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	// Once the transaction is in the pool, it is public. A new API does not have the earlier
+	// outcome of the same submission.
+	require.NoError(t, ethservice.TxPool().AddLocal(tx))
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, MinPrivateTxRatio: 1})
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrTooManyPublicTransactions)
+}
+
+func TestValidateBuilderSubmissionBatch(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	profit := big.NewInt(21000 * baseFee.Int64())
+
+	valid := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, profit)
+	overpaid := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, new(big.Int).Add(profit, common.Big1))
+	nilPayload := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, profit)
+	nilPayload.ExecutionPayload = nil
+	nilMessage := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, profit)
+	nilMessage.Message = nil
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, MaxConcurrentValidations: 2})
+	batch := []*BuilderBlockValidationRequestV2{valid, overpaid, nilPayload, valid, nilMessage, nil}
+	results := api.ValidateBuilderSubmissionBatch(context.Background(), batch)
+	require.Len(t, results, len(batch))
+	for i, req := range batch {
+		expected := ""
+		if err := api.ValidateBuilderSubmissionV2(context.Background(), req); err != nil {
+			expected = err.Error()
+		}
+		require.Equal(t, expected, results[i], "request %d", i)
+	}
+	require.Empty(t, results[0])
+	require.NotEmpty(t, results[1])
+	require.Equal(t, "nil execution payload", results[2])
+	require.Equal(t, "nil bid message", results[4])
+	require.Equal(t, "nil request", results[5])
+
+	// A panicking validation only fails its own submission.
+	hooks := ValidationHooks{PreValidation: func(req interface{}) error {
+		if req == overpaid {
+			panic("boom")
+		}
+		return nil
+	}}
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, Hooks: hooks})
+	results = api.ValidateBuilderSubmissionBatch(context.Background(), []*BuilderBlockValidationRequestV2{overpaid, valid})
+	require.Equal(t, []string{"panic validating submission: boom", ""}, results)
+}
+
+func TestValidateBuilderSubmissionV2_ProfilingMode(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	profit := big.NewInt(21000 * baseFee.Int64())
+
+	// The labels do not change the outcome of the replay.
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, ProfilingMode: true})
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, profit)))
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV2(context.Background(), buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, new(big.Int).Add(profit, common.Big1))), "payment")
+}
+
+func TestValidateBuilderSubmissionV2_ValidationTimeout(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChainWithGasLimit(20, 30_000_000)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	gasPrice := big.NewInt(2 * baseFee.Int64())
+	signer := types.LatestSigner(bc.Config())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	nonce := statedb.GetNonce(testAddr)
+
+	// The contract jumps back to its start until it runs out of gas.
+	loopCode := common.FromHex("635b6000566000526004601cf3")
+	create, _ := types.SignTx(types.NewContractCreation(nonce, common.Big0, 100000, gasPrice, loopCode), signer, testKey)
+	call, _ := types.SignTx(types.NewTransaction(nonce+1, crypto.CreateAddress(testAddr, nonce), common.Big0, lastBlock.GasLimit()-200000, gasPrice, nil), signer, testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{create, call}, nil, common.Big0)
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true})
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, ValidationTimeout: time.Millisecond})
+	start := time.Now()
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestValidateBuilderSubmissionV2_ProfitMode(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	signer := types.LatestSigner(bc.Config())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), signer, testKey)
+	fees := big.NewInt(21000 * baseFee.Int64())
+
+	// The fees of the block go to the fee recipient.
+	feesReq := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, fees)
+	feesReqTooHigh := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, new(big.Int).Add(fees, common.Big1))
+
+	// The builder collects the fees and pays the fee recipient in the last transaction.
+	value := big.NewInt(1_000_000)
+	payment, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testBuilderAddr), testValidatorAddr, value, 21000, baseFee, nil), signer, testBuilderKey)
+	execData, err := buildBlock(buildBlockArgs{
+		parentHash:    lastBlock.Hash(),
+		parentRoot:    lastBlock.Root(),
+		feeRecipient:  testBuilderAddr,
+		txs:           types.Transactions{tx, payment},
+		number:        lastBlock.NumberU64() + 1,
+		gasLimit:      lastBlock.GasLimit(),
+		timestamp:     lastBlock.Time() + 5,
+		baseFeePerGas: baseFee,
+	}, bc)
+	require.NoError(t, err)
+	transferReq, err := executableDataToBlockValidationRequest(execData, testValidatorAddr, value, ComputeWithdrawalsRoot(nil))
+	require.NoError(t, err)
+	transferReqWrongValue, err := executableDataToBlockValidationRequest(execData, testValidatorAddr, big.NewInt(999_999), ComputeWithdrawalsRoot(nil))
+	require.NoError(t, err)
+
+	t.Run("balanceDiff", func(t *testing.T) {
+		api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{ProfitMode: ProfitModeBalanceDiff, AllowIndirectPayment: true})
+		require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), feesReq))
+		require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), transferReq))
+		require.Error(t, api.ValidateBuilderSubmissionV2(context.Background(), feesReqTooHigh))
+	})
+
+	t.Run("feesOnly", func(t *testing.T) {
+		api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{ProfitMode: ProfitModeFeesOnly, AllowIndirectPayment: true})
+		require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), feesReq))
+
+		err := api.ValidateBuilderSubmissionV2(context.Background(), feesReqTooHigh)
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		require.Equal(t, CodeInsufficientProfit, verr.Code)
+
+		require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), transferReq), ErrProfitModeViolation)
+	})
+
+	t.Run("coinbaseTransfer", func(t *testing.T) {
+		api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{ProfitMode: ProfitModeCoinbaseTransfer})
+		require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), transferReq))
+
+		err := api.ValidateBuilderSubmissionV2(context.Background(), transferReqWrongValue)
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		require.Equal(t, CodeInsufficientProfit, verr.Code)
+
+		require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), feesReq), ErrProfitModeViolation)
+	})
+}
+
+func TestValidateBuilderSubmissionV2_VerifyBuilderSignature(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	sk, pk, err := bls.GenerateNewKeypair()
+	require.NoError(t, err)
+	copy(req.Message.BuilderPubkey[:], bls.PublicKeyToBytes(pk))
+
+	cfg := BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, VerifyBuilderSignature: true, GenesisForkVersion: phase0.Version{0x02}}
+	api := newBlockValidationAPI(ethservice, nil, cfg)
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrInvalidBuilderSignature)
+
+	req.Signature, err = ssz.SignMessage(req.Message, builderSigningDomain(cfg.GenesisForkVersion), sk)
+	require.NoError(t, err)
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+}
+
+func TestValidateBuilderSubmission_AllowedFeeRecipients(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	bellatrixNode, bellatrixService := startEthService(t, genesis, preMergeBlocks)
+	bellatrixService.Merger().ReachTTD()
+	defer bellatrixNode.Close()
+
+	capellaGenesis := *genesis
+	capellaConfig := *genesis.Config
+	shanghaiTime := lastBlock.Time() + 5
+	capellaConfig.ShanghaiTime = &shanghaiTime
+	capellaGenesis.Config = &capellaConfig
+	capellaNode, capellaService := startEthService(t, &capellaGenesis, preMergeBlocks)
+	capellaService.Merger().ReachTTD()
+	defer capellaNode.Close()
+
+	baseFee := misc.CalcBaseFee(genesis.Config, lastBlock.Header())
+	statedb, _ := bellatrixService.BlockChain().StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(genesis.Config), testKey)
+	profit := big.NewInt(21000 * baseFee.Int64())
+	reqV1 := buildTestRequestV1(t, bellatrixService.BlockChain(), lastBlock, types.Transactions{tx}, profit)
+	reqV2 := buildTestRequestV2(t, capellaService.BlockChain(), lastBlock, types.Transactions{tx}, nil, profit)
+
+	for _, tt := range []struct {
+		name    string
+		allowed []common.Address
+		valid   bool
+	}{
+		{"no allowlist", nil, true},
+		{"allowed", []common.Address{{0x01}, testValidatorAddr}, true},
+		{"not allowed", []common.Address{{0x01}}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := BlockValidationConfig{UseBalanceDiffProfit: true, AllowedFeeRecipients: tt.allowed}
+			apiV1 := newBlockValidationAPI(bellatrixService, nil, cfg)
+			apiV2 := newBlockValidationAPI(capellaService, nil, cfg)
+			errV1 := apiV1.ValidateBuilderSubmissionV1(context.Background(), reqV1)
+			errV2 := apiV2.ValidateBuilderSubmissionV2(context.Background(), reqV2)
+			if tt.valid {
+				require.NoError(t, errV1)
+				require.NoError(t, errV2)
+				return
+			}
+			require.ErrorIs(t, errV1, ErrUnknownFeeRecipient)
+			require.ErrorIs(t, errV2, ErrUnknownFeeRecipient)
+		})
+	}
+}
+
+func TestValidateBuilderSubmission_MinProfit(t *testing.T) {
+	recipient, other := bellatrix.ExecutionAddress{0x01}, bellatrix.ExecutionAddress{0x02}
+	api := newBlockValidationAPI(nil, nil, BlockValidationConfig{
+		MinProfit: big.NewInt(100),
+		MinProfitByRecipient: map[common.Address]*big.Int{
+			common.Address(recipient): big.NewInt(10),
+		},
+	})
+
+	for _, tt := range []struct {
+		name      string
+		recipient bellatrix.ExecutionAddress
+		value     uint64
+		valid     bool
+	}{
+		{"global floor met", other, 100, true},
+		{"below global floor", other, 99, false},
+		{"per-recipient floor takes precedence", recipient, 10, true},
+		{"below per-recipient floor", recipient, 9, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			message := &apiv1.BidTrace{ProposerFeeRecipient: tt.recipient, Value: uint256.NewInt(tt.value)}
+			err := api.checkMinProfit(message)
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Equal(t, CodeInsufficientProfit, validationErr.Code)
+
+			// The floor is checked before the payload is even converted to a block.
+			require.ErrorAs(t, api.ValidateBuilderSubmissionV1(context.Background(), &BuilderBlockValidationRequest{
+				SubmitBlockRequest: bellatrixapi.SubmitBlockRequest{Message: message, ExecutionPayload: &bellatrix.ExecutionPayload{}},
+			}), &validationErr)
+			require.Equal(t, CodeInsufficientProfit, validationErr.Code)
+			require.ErrorAs(t, api.ValidateBuilderSubmissionV2(context.Background(), &BuilderBlockValidationRequestV2{
+				SubmitBlockRequest: capellaapi.SubmitBlockRequest{Message: message, ExecutionPayload: &capella.ExecutionPayload{}},
+			}), &validationErr)
+			require.Equal(t, CodeInsufficientProfit, validationErr.Code)
+		})
+	}
+
+	// Without a floor any value is accepted.
+	require.NoError(t, newBlockValidationAPI(nil, nil, BlockValidationConfig{}).checkMinProfit(&apiv1.BidTrace{Value: uint256.NewInt(0)}))
+}
+
+func TestBuilderBlockValidationRequest_MaxPayloadBytes(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	reqV2 := buildTestRequestV2(t, ethservice.BlockChain(), lastBlock, nil, nil, common.Big0)
+	reqV1, err := DowngradeToV1(reqV2)
+	require.NoError(t, err)
+
+	// encode adds the registered gas limit to the submission and pads the encoding to size bytes.
+	encode := func(submission interface{}, size int) []byte {
+		encoded, err := json.Marshal(submission)
+		require.NoError(t, err)
+		var fields map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(encoded, &fields))
+		fields["registered_gas_limit"] = json.RawMessage(`"30000000"`)
+		encoded, err = json.Marshal(fields)
+		require.NoError(t, err)
+		padded := append(encoded[:len(encoded)-1], bytes.Repeat([]byte{' '}, size-len(encoded))...)
+		return append(padded, '}')
+	}
+
+	var decodedV1 BuilderBlockValidationRequest
+	require.NoError(t, json.Unmarshal(encode(&reqV1.SubmitBlockRequest, 1<<20), &decodedV1))
+	require.Equal(t, uint64(30_000_000), decodedV1.RegisteredGasLimit)
+	require.Equal(t, reqV1.ExecutionPayload.BlockHash, decodedV1.ExecutionPayload.BlockHash)
+	require.ErrorIs(t, json.Unmarshal(encode(&reqV1.SubmitBlockRequest, 3<<20), &decodedV1), ErrPayloadTooLarge)
+
+	var decodedV2 BuilderBlockValidationRequestV2
+	require.NoError(t, json.Unmarshal(encode(&reqV2.SubmitBlockRequest, 1<<20), &decodedV2))
+	require.Equal(t, uint64(30_000_000), decodedV2.RegisteredGasLimit)
+	require.Equal(t, reqV2.ExecutionPayload.BlockHash, decodedV2.ExecutionPayload.BlockHash)
+	require.ErrorIs(t, json.Unmarshal(encode(&reqV2.SubmitBlockRequest, 3<<20), &decodedV2), ErrPayloadTooLarge)
+}
+
+func TestValidateBuilderSubmissionWithResult(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	bellatrixNode, bellatrixService := startEthService(t, genesis, preMergeBlocks)
+	bellatrixService.Merger().ReachTTD()
+	defer bellatrixNode.Close()
+
+	capellaGenesis := *genesis
+	capellaConfig := *genesis.Config
+	shanghaiTime := lastBlock.Time() + 5
+	capellaConfig.ShanghaiTime = &shanghaiTime
+	capellaGenesis.Config = &capellaConfig
+	capellaNode, capellaService := startEthService(t, &capellaGenesis, preMergeBlocks)
+	capellaService.Merger().ReachTTD()
+	defer capellaNode.Close()
+
+	baseFee := misc.CalcBaseFee(genesis.Config, lastBlock.Header())
+	statedb, _ := bellatrixService.BlockChain().StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(genesis.Config), testKey)
+	fees := big.NewInt(21000 * baseFee.Int64())
+	// Claiming less than the fees is valid, the computed profit is reported regardless.
+	claimed := new(big.Int).Sub(fees, common.Big1)
+	reqV1 := buildTestRequestV1(t, bellatrixService.BlockChain(), lastBlock, types.Transactions{tx}, claimed)
+	reqV2 := buildTestRequestV2(t, capellaService.BlockChain(), lastBlock, types.Transactions{tx}, nil, claimed)
+
+	apiV1, err := NewBlockValidationAPI(bellatrixService, nil, true, nil)
+	require.NoError(t, err)
+	apiV2, err := NewBlockValidationAPI(capellaService, nil, true, nil)
+	require.NoError(t, err)
+	resultV1, err := apiV1.ValidateBuilderSubmissionV1WithResult(context.Background(), reqV1)
+	require.NoError(t, err)
+	resultV2, err := apiV2.ValidateBuilderSubmissionV2WithResult(context.Background(), reqV2)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		result    *ValidatePayloadResult
+		blockHash phase0.Hash32
+	}{
+		{resultV1, reqV1.ExecutionPayload.BlockHash},
+		{resultV2, reqV2.ExecutionPayload.BlockHash},
+	} {
+		require.Equal(t, fees, tt.result.ComputedProfit)
+		require.Equal(t, fees, tt.result.FeeRecipientDelta)
+		require.Equal(t, uint64(21000), tt.result.GasUsed)
+		require.Equal(t, common.Hash(tt.blockHash), tt.result.BlockHash)
+		require.Equal(t, float64(21000)/float64(lastBlock.GasLimit()), tt.result.GasEfficiency)
+		require.Equal(t, []TxGasEntry{{TxHash: tx.Hash(), GasUsed: 21000, GasPrice: big.NewInt(2 * baseFee.Int64())}}, tt.result.Transactions)
+	}
+
+	reqV2.Message.Value = uint256.MustFromBig(new(big.Int).Add(fees, common.Big1))
+	updatePayloadHashV2(t, reqV2)
+	resultV2, err = apiV2.ValidateBuilderSubmissionV2WithResult(context.Background(), reqV2)
+	require.Error(t, err)
+	require.Nil(t, resultV2)
+
+	stats := apiV2.GetBuilderStats()[reqV2.Message.BuilderPubkey.String()]
+	require.Equal(t, uint64(2), stats.TotalSubmissions)
+	require.Equal(t, uint64(1), stats.SuccessfulValidations)
+	require.Equal(t, reqV2.Message.Slot, stats.LastSeenSlot)
+}
+
+// fuzzSeedRequest encodes a minimal submission with the given extra top level fields.
+func fuzzSeedRequest(t testing.TB, request interface{}, fields map[string]string) []byte {
+	t.Helper()
+	data, err := json.Marshal(request)
+	require.NoError(t, err)
+	var object map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &object))
+	for name, value := range fields {
+		object[name] = json.RawMessage(value)
+	}
+	data, err = json.Marshal(object)
+	require.NoError(t, err)
+	return data
+}
+
+func FuzzUnmarshalBuilderBlockValidationRequest(f *testing.F) {
+	request := &bellatrixapi.SubmitBlockRequest{
+		Message:          &apiv1.BidTrace{Value: uint256.NewInt(0)},
+		ExecutionPayload: &bellatrix.ExecutionPayload{Transactions: []bellatrix.Transaction{}},
+	}
+	f.Add(fuzzSeedRequest(f, request, map[string]string{"registered_gas_limit": `"30000000"`}))
+	f.Add(fuzzSeedRequest(f, request, map[string]string{"registered_gas_limit": `"30000000"`, "unknown": `{"a":[1,2]}`}))
+	f.Add(fuzzSeedRequest(f, request, map[string]string{"registered_gas_limit": `30000000`}))
+	f.Add([]byte(`{}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var r BuilderBlockValidationRequest
+		if err := r.UnmarshalJSON(data); err != nil {
+			require.Nil(t, r.Message)
+			require.Nil(t, r.ExecutionPayload)
+			return
+		}
+		require.NotNil(t, r.Message)
+		require.NotNil(t, r.ExecutionPayload)
+	})
+}
+
+func FuzzUnmarshalBuilderBlockValidationRequestV2(f *testing.F) {
+	request := &capellaapi.SubmitBlockRequest{
+		Message: &apiv1.BidTrace{Value: uint256.NewInt(0)},
+		ExecutionPayload: &capella.ExecutionPayload{
+			Transactions: []bellatrix.Transaction{},
+			Withdrawals:  []*capella.Withdrawal{},
+		},
+	}
+	emptyRoot := fmt.Sprintf("%q", ComputeWithdrawalsRoot(nil).Hex())
+	f.Add(fuzzSeedRequest(f, request, map[string]string{"registered_gas_limit": `"30000000"`, "withdrawals_root": emptyRoot}))
+	f.Add(fuzzSeedRequest(f, request, map[string]string{"registered_gas_limit": `"30000000"`, "withdrawals_root": emptyRoot, "unknown": `{"a":[1,2]}`}))
+	f.Add(fuzzSeedRequest(f, request, map[string]string{"registered_gas_limit": `"30000000"`, "withdrawals_root": fmt.Sprintf("%q", common.Hash{0x01}.Hex())}))
+	f.Add(fuzzSeedRequest(f, request, map[string]string{"registered_gas_limit": `30000000`, "withdrawals_root": emptyRoot}))
+	f.Add([]byte(`{}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var r BuilderBlockValidationRequestV2
+		if err := r.UnmarshalJSON(data); err != nil {
+			require.Nil(t, r.Message)
+			require.Nil(t, r.ExecutionPayload)
+			return
+		}
+		require.NotNil(t, r.Message)
+		require.NotNil(t, r.ExecutionPayload)
+	})
+}
+
+func TestValidateBuilderSubmissionV2_NonCanonicalParent(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	cfg := BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true}
+
+	// A known block with state that does not become the head of the chain.
+	sideReq := buildTestRequestV2(t, bc, lastBlock, nil, nil, common.Big0)
+	side, err := engine.ExecutionPayloadV2ToBlock(sideReq.ExecutionPayload)
+	require.NoError(t, err)
+	require.NoError(t, bc.InsertBlockWithoutSetHead(side))
+	require.Equal(t, lastBlock.Hash(), bc.CurrentBlock().Hash())
+
+	baseFee := misc.CalcBaseFee(bc.Config(), side.Header())
+	statedb, _ := bc.StateAt(side.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, side, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	api := newBlockValidationAPI(ethservice, nil, cfg)
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrNonCanonicalParent)
+
+	cfg.AllowNonCanonicalParent = true
+	api = newBlockValidationAPI(ethservice, nil, cfg)
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	// Blocks building on an unknown parent are rejected either way.
+	req.ExecutionPayload.ParentHash = phase0.Hash32{0x01}
+	req.Message.ParentHash = phase0.Hash32{0x01}
+	updatePayloadHashV2(t, req)
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrUnknownParent)
+}
+
+func TestValidateBuilderSubmissionV2_BuilderCoinbaseRegistry(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	cfg := BlockValidationConfig{
+		UseBalanceDiffProfit:    true,
+		AllowIndirectPayment:    true,
+		BuilderCoinbaseRegistry: map[[48]byte]common.Address{req.Message.BuilderPubkey: testBuilderAddr},
+	}
+	api := newBlockValidationAPI(ethservice, nil, cfg)
+	require.Equal(t, ErrCoinbaseMismatch{Got: testValidatorAddr, Expected: testBuilderAddr}, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	cfg.BuilderCoinbaseRegistry = map[[48]byte]common.Address{req.Message.BuilderPubkey: testValidatorAddr}
+	api = newBlockValidationAPI(ethservice, nil, cfg)
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+}
+
+func TestValidateBuilderSubmissionV2_ForbidZeroAddressTransactions(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	cfg := BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true}
+	api := newBlockValidationAPI(ethservice, nil, cfg)
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	cfg.ForbidZeroAddressTransactions = true
+	api = newBlockValidationAPI(ethservice, nil, cfg)
+	require.Equal(t, ErrZeroAddressTransaction{TxIndex: 0, TxHash: tx.Hash()}, api.ValidateBuilderSubmissionV2(context.Background(), req))
+}
+
+func TestMaxSlot(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	defer n.Close()
+
+	head := ethservice.BlockChain().CurrentHeader()
+	require.Equal(t, uint64(math.MaxUint64), newBlockValidationAPI(ethservice, nil, BlockValidationConfig{}).maxSlot())
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{BeaconGenesisTime: head.Time - 24})
+	require.Equal(t, uint64(3), api.maxSlot())
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{BeaconGenesisTime: head.Time + 12})
+	require.Equal(t, uint64(1), api.maxSlot())
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{BeaconGenesisTime: head.Time - 24, SlotDuration: 500 * time.Millisecond})
+	require.Equal(t, uint64(25), api.maxSlot())
 }
@@ -0,0 +1,252 @@
+package blockvalidation
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	auditFlushInterval = 100 * time.Millisecond
+	auditDateLayout    = "2006-01-02"
+)
+
+// auditEntry is a single line of the audit log.
+type auditEntry struct {
+	Timestamp     string `json:"timestamp"`
+	BuilderPubkey string `json:"builderPubkey"`
+	BlockHash     string `json:"blockHash"`
+	Slot          uint64 `json:"slot"`
+	BlockNumber   uint64 `json:"blockNumber"`
+	// Profits in wei, the computed one is only known for valid blocks.
+	ClaimedProfit  string `json:"claimedProfit"`
+	ComputedProfit string `json:"computedProfit,omitempty"`
+	// Both empty for valid blocks.
+	ErrorCode    string `json:"errorCode"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// newAuditEntry describes a validation attempt, the block and result are nil if unknown.
+func newAuditEntry(msg *apiv1.BidTrace, block *types.Block, result *core.PayloadValidationResult, err error) auditEntry {
+	entry := auditEntry{
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if err != nil {
+		entry.ErrorCode = auditErrorCode(err)
+		entry.ErrorMessage = err.Error()
+	}
+	if msg != nil {
+		entry.BuilderPubkey = msg.BuilderPubkey.String()
+		entry.BlockHash = msg.BlockHash.String()
+		entry.Slot = msg.Slot
+		if msg.Value != nil {
+			entry.ClaimedProfit = msg.Value.Dec()
+		}
+	}
+	if block != nil {
+		entry.BlockNumber = block.NumberU64()
+	}
+	if result != nil && result.Profit != nil {
+		entry.ComputedProfit = result.Profit.String()
+	}
+	return entry
+}
+
+// auditErrorCodes are the codes of the sentinel errors. Validation errors are identified by their
+// code, the other struct errors by their type name.
+var auditErrorCodes = map[error]string{
+	ErrTooManyWithdrawals:             "ErrTooManyWithdrawals",
+	ErrMergeNotActivated:              "ErrMergeNotActivated",
+	ErrNilTransactions:                "ErrNilTransactions",
+	ErrCustomEIPsNotAllowed:           "ErrCustomEIPsNotAllowed",
+	ErrCumulativeGasOverflow:          "ErrCumulativeGasOverflow",
+	ErrWithdrawalAmountMismatch:       "ErrWithdrawalAmountMismatch",
+	ErrGenesisHashMismatch:            "ErrGenesisHashMismatch",
+	ErrPayloadRoundTripMismatch:       "ErrPayloadRoundTripMismatch",
+	ErrTooManyContractAccesses:        "ErrTooManyContractAccesses",
+	ErrProfitMispriced:                "ErrProfitMispriced",
+	ErrExcessiveTrieDepth:             "ErrExcessiveTrieDepth",
+	ErrInvalidWithdrawalListSignature: "ErrInvalidWithdrawalListSignature",
+	ErrInvalidBuilderSignature:        "ErrInvalidBuilderSignature",
+	ErrUnknownFeeRecipient:            "ErrUnknownFeeRecipient",
+	ErrPayloadTooLarge:                "ErrPayloadTooLarge",
+	ErrTooManyPublicTransactions:      "ErrTooManyPublicTransactions",
+	ErrProfitModeViolation:            "ErrProfitModeViolation",
+	ErrDowngradeNotPossible:           "ErrDowngradeNotPossible",
+	ErrSSZDisabled:                    "ErrSSZDisabled",
+	ErrBuilderStatsResetDisabled:      "ErrBuilderStatsResetDisabled",
+	ErrExtraDataViolation:             "ErrExtraDataViolation",
+	ErrTooManyTransactions:            "ErrTooManyTransactions",
+	ErrUnknownParent:                  "ErrUnknownParent",
+	ErrNonCanonicalParent:             "ErrNonCanonicalParent",
+	ErrCircuitOpen:                    "ErrCircuitOpen",
+	ErrBeaconValidationFailed:         "ErrBeaconValidationFailed",
+	ErrVMConfigOverridesNotAllowed:    "ErrVMConfigOverridesNotAllowed",
+	ErrInvalidVMConfigOverrides:       "ErrInvalidVMConfigOverrides",
+	ErrAnomalousProfit:                "ErrAnomalousProfit",
+	ErrNoEthService:                   "ErrNoEthService",
+}
+
+var packagePath = reflect.TypeOf(auditEntry{}).PkgPath()
+
+// auditErrorCode returns a stable identifier for the validation error, or "unknown" for errors
+// that do not originate from this package.
+func auditErrorCode(err error) string {
+	for sentinel, code := range auditErrorCodes {
+		if errors.Is(err, sentinel) {
+			return code
+		}
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return string(validationErr.Code)
+	}
+	for ; err != nil; err = errors.Unwrap(err) {
+		if t := reflect.TypeOf(err); t.PkgPath() == packagePath {
+			return t.Name()
+		}
+	}
+	return "unknown"
+}
+
+// auditLog appends validation attempts to a file as NDJSON. Writes are buffered and flushed
+// periodically. At midnight UTC the file is moved aside with the date appended to its name.
+type auditLog struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+	buf  *bufio.Writer
+	day  string // UTC date of the entries in the current file
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newAuditLog(path string) (*auditLog, error) {
+	a := &auditLog{path: path, quit: make(chan struct{})}
+	// Move aside a file left over from an earlier day before appending to it.
+	if info, err := os.Stat(path); err == nil {
+		if day := info.ModTime().UTC().Format(auditDateLayout); day != time.Now().UTC().Format(auditDateLayout) {
+			a.day = day
+			if err := a.archive(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := a.open(time.Now()); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *auditLog) open(now time.Time) error {
+	file, err := os.OpenFile(a.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	a.file = file
+	a.buf = bufio.NewWriter(file)
+	a.day = now.UTC().Format(auditDateLayout)
+	return nil
+}
+
+func (a *auditLog) archive() error {
+	return os.Rename(a.path, a.path+"."+a.day)
+}
+
+// rotate starts a new file if the UTC date has changed. The lock must be held.
+func (a *auditLog) rotate(now time.Time) error {
+	if now.UTC().Format(auditDateLayout) == a.day {
+		return nil
+	}
+	if err := a.closeFile(); err != nil {
+		return err
+	}
+	if err := a.archive(); err != nil {
+		return err
+	}
+	return a.open(now)
+}
+
+func (a *auditLog) closeFile() error {
+	if a.file == nil {
+		return nil
+	}
+	if err := a.buf.Flush(); err != nil {
+		return err
+	}
+	err := a.file.Close()
+	a.file, a.buf = nil, nil
+	return err
+}
+
+// record appends an entry for a validation attempt.
+func (a *auditLog) record(entry auditEntry) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Error("failed to encode audit log entry", "err", err)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.rotate(time.Now()); err != nil {
+		log.Error("failed to rotate audit log", "path", a.path, "err", err)
+	}
+	if a.buf == nil {
+		return
+	}
+	a.buf.Write(append(line, '\n'))
+}
+
+func (a *auditLog) flush() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err := a.rotate(time.Now()); err != nil {
+		log.Error("failed to rotate audit log", "path", a.path, "err", err)
+	}
+	if a.buf != nil {
+		if err := a.buf.Flush(); err != nil {
+			log.Error("failed to write audit log", "path", a.path, "err", err)
+		}
+	}
+}
+
+// Start implements node.Lifecycle, starting the periodic flush.
+func (a *auditLog) Start() error {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(auditFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				a.flush()
+			case <-a.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop implements node.Lifecycle, flushing and closing the file.
+func (a *auditLog) Stop() error {
+	close(a.quit)
+	a.wg.Wait()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.closeFile()
+}
@@ -0,0 +1,216 @@
+package blockvalidation
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	bellatrixapi "github.com/attestantio/go-builder-client/api/bellatrix"
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func readAuditLog(t *testing.T, path string) []auditEntry {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var entries []auditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry auditEntry
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.NoError(t, scanner.Err())
+	return entries
+}
+
+func TestAuditErrorCode(t *testing.T) {
+	require.Equal(t, "ErrTooManyWithdrawals", auditErrorCode(fmt.Errorf("%w: 17, max 16", ErrTooManyWithdrawals)))
+	require.Equal(t, "ErrCoinbaseMismatch", auditErrorCode(ErrCoinbaseMismatch{}))
+	require.Equal(t, "ErrDuplicateTransaction", auditErrorCode(fmt.Errorf("invalid block: %w", ErrDuplicateTransaction{})))
+	require.Equal(t, "unknown", auditErrorCode(errors.New("nil execution payload")))
+	require.Equal(t, "ErrNilPayload", auditErrorCode(newValidationError(CodeNilPayload, "nil execution payload")))
+	// Sentinels take precedence over the code of the validation error wrapping them.
+	require.Equal(t, "ErrInvalidWithdrawalListSignature", auditErrorCode(wrapValidationError(CodeInvalidSignature, ErrInvalidWithdrawalListSignature)))
+}
+
+// TestAuditErrorCodesComplete checks that every sentinel error declared in errors.go has a code.
+func TestAuditErrorCodesComplete(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "errors.go", nil, 0)
+	require.NoError(t, err)
+
+	codes := make(map[string]string)
+	for sentinel, code := range auditErrorCodes {
+		codes[code] = sentinel.Error()
+	}
+	var sentinels int
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			for i, name := range spec.(*ast.ValueSpec).Names {
+				if !strings.HasPrefix(name.Name, "Err") {
+					continue
+				}
+				sentinels++
+				message, _ := strconv.Unquote(spec.(*ast.ValueSpec).Values[i].(*ast.CallExpr).Args[0].(*ast.BasicLit).Value)
+				require.Contains(t, codes, name.Name)
+				require.Equal(t, message, codes[name.Name], name.Name)
+			}
+		}
+	}
+	require.Equal(t, sentinels, len(auditErrorCodes))
+}
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.ndjson")
+	audit, err := newAuditLog(path)
+	require.NoError(t, err)
+	require.NoError(t, audit.Start())
+
+	msg := &apiv1.BidTrace{Slot: 42, BuilderPubkey: phase0.BLSPubKey{0x01}, BlockHash: phase0.Hash32{0x02}}
+	audit.record(newAuditEntry(msg, nil, nil, ErrCoinbaseMismatch{}))
+	audit.record(newAuditEntry(nil, nil, nil, errors.New("nil execution payload")))
+
+	// Entries are written out by the periodic flush.
+	require.Eventually(t, func() bool {
+		info, err := os.Stat(path)
+		return err == nil && info.Size() > 0
+	}, time.Second, 10*time.Millisecond)
+
+	entries := readAuditLog(t, path)
+	require.Len(t, entries, 2)
+	require.Equal(t, uint64(42), entries[0].Slot)
+	require.Equal(t, msg.BuilderPubkey.String(), entries[0].BuilderPubkey)
+	require.Equal(t, msg.BlockHash.String(), entries[0].BlockHash)
+	require.Equal(t, "ErrCoinbaseMismatch", entries[0].ErrorCode)
+	require.Equal(t, ErrCoinbaseMismatch{}.Error(), entries[0].ErrorMessage)
+	_, err = time.Parse(time.RFC3339Nano, entries[0].Timestamp)
+	require.NoError(t, err)
+	require.Equal(t, "unknown", entries[1].ErrorCode)
+	require.Zero(t, entries[1].Slot)
+
+	// Crossing midnight UTC moves the current file aside.
+	day := audit.day
+	audit.mu.Lock()
+	require.NoError(t, audit.rotate(time.Now().Add(24*time.Hour)))
+	audit.mu.Unlock()
+	audit.record(newAuditEntry(msg, nil, nil, ErrTooManyWithdrawals))
+	require.NoError(t, audit.Stop())
+
+	require.Len(t, readAuditLog(t, path+"."+day), 2)
+	entries = readAuditLog(t, path)
+	require.Len(t, entries, 1)
+	require.Equal(t, "ErrTooManyWithdrawals", entries[0].ErrorCode)
+}
+
+func TestAuditLogEarlyRejections(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.ndjson")
+	audit, err := newAuditLog(path)
+	require.NoError(t, err)
+	require.NoError(t, audit.Start())
+
+	// Submissions rejected before validation starts are audited as well.
+	api := newBlockValidationAPI(nil, nil, BlockValidationConfig{})
+	api.audit, api.headBreaker = audit, &headBreaker{open: true}
+	msg := &apiv1.BidTrace{Slot: 42, BuilderPubkey: phase0.BLSPubKey{0x01}}
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV1(context.Background(), &BuilderBlockValidationRequest{SubmitBlockRequest: bellatrixapi.SubmitBlockRequest{Message: msg}}), ErrCircuitOpen)
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), &BuilderBlockValidationRequestV2{SubmitBlockRequest: capellaapi.SubmitBlockRequest{Message: msg}}), ErrCircuitOpen)
+	require.NoError(t, audit.Stop())
+
+	entries := readAuditLog(t, path)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		require.Equal(t, uint64(42), entry.Slot)
+		require.Equal(t, "ErrCircuitOpen", entry.ErrorCode)
+	}
+}
+
+func TestAuditEntryJSON(t *testing.T) {
+	msg := &apiv1.BidTrace{Slot: 42, BuilderPubkey: phase0.BLSPubKey{0x01}, BlockHash: phase0.Hash32{0x02}, Value: uint256.NewInt(100)}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(7)})
+
+	encode := func(entry auditEntry) map[string]interface{} {
+		enc, err := json.Marshal(entry)
+		require.NoError(t, err)
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(enc, &fields))
+		return fields
+	}
+
+	valid := encode(newAuditEntry(msg, block, &core.PayloadValidationResult{Profit: big.NewInt(90)}, nil))
+	require.ElementsMatch(t, []string{"timestamp", "builderPubkey", "blockHash", "slot", "blockNumber", "claimedProfit", "computedProfit", "errorCode", "errorMessage"}, mapKeys(valid))
+	require.Equal(t, float64(7), valid["blockNumber"])
+	require.Equal(t, "100", valid["claimedProfit"])
+	require.Equal(t, "90", valid["computedProfit"])
+	require.Empty(t, valid["errorCode"])
+	require.Empty(t, valid["errorMessage"])
+
+	// The computed profit is missing for rejected blocks.
+	rejected := encode(newAuditEntry(msg, block, nil, ErrTooManyWithdrawals))
+	require.ElementsMatch(t, []string{"timestamp", "builderPubkey", "blockHash", "slot", "blockNumber", "claimedProfit", "errorCode", "errorMessage"}, mapKeys(rejected))
+	require.Equal(t, "100", rejected["claimedProfit"])
+	require.Equal(t, "ErrTooManyWithdrawals", rejected["errorCode"])
+	require.Equal(t, ErrTooManyWithdrawals.Error(), rejected["errorMessage"])
+}
+
+func mapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestAuditLogAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte("{\"slot\":1}\n"), 0o644))
+
+	// The existing entries of the same day are kept.
+	audit, err := newAuditLog(path)
+	require.NoError(t, err)
+	audit.record(newAuditEntry(&apiv1.BidTrace{Slot: 2}, nil, nil, nil))
+	require.NoError(t, audit.Stop())
+
+	entries := readAuditLog(t, path)
+	require.Len(t, entries, 2)
+	require.Equal(t, uint64(1), entries[0].Slot)
+	require.Equal(t, uint64(2), entries[1].Slot)
+}
+
+func TestAuditLogArchivesStaleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte("{}\n"), 0o644))
+	yesterday := time.Now().Add(-24 * time.Hour)
+	require.NoError(t, os.Chtimes(path, yesterday, yesterday))
+
+	audit, err := newAuditLog(path)
+	require.NoError(t, err)
+	require.NoError(t, audit.Stop())
+
+	_, err = os.Stat(path + "." + yesterday.UTC().Format(auditDateLayout))
+	require.NoError(t, err)
+	require.Empty(t, readAuditLog(t, path))
+}
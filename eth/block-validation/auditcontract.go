@@ -0,0 +1,140 @@
+package blockvalidation
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	auditContractMethod = "recordValidation"
+	auditContractGas    = 100_000
+	// auditContractQueueSize is how many validations wait to be recorded at most. Validations
+	// are dropped while the queue is full.
+	auditContractQueueSize = 256
+)
+
+type auditRecord struct {
+	block  *types.Block
+	profit *big.Int
+}
+
+// auditContract records successful validations on chain by calling
+// recordValidation(bytes32 blockHash, uint256 profit, address builder) on the audit contract.
+// A call would not persist anything, so the call is sent as a transaction signed with the
+// configured key through the local transaction pool. Transactions are sent in the background,
+// off the validation path.
+type auditContract struct {
+	backend *eth.Ethereum
+	address common.Address
+	abi     abi.ABI
+	key     *ecdsa.PrivateKey
+	from    common.Address
+
+	queue chan auditRecord
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newAuditContract(backend *eth.Ethereum, address common.Address, abiJSON string, key *ecdsa.PrivateKey) (*auditContract, error) {
+	if key == nil {
+		return nil, errors.New("audit contract requires a signing key")
+	}
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit contract ABI: %w", err)
+	}
+	method, ok := parsed.Methods[auditContractMethod]
+	if !ok {
+		return nil, fmt.Errorf("audit contract ABI has no %s method", auditContractMethod)
+	}
+	if sig := method.Sig; sig != "recordValidation(bytes32,uint256,address)" {
+		return nil, fmt.Errorf("unexpected audit contract method %s", sig)
+	}
+	return &auditContract{
+		backend: backend,
+		address: address,
+		abi:     parsed,
+		key:     key,
+		from:    crypto.PubkeyToAddress(key.PublicKey),
+		queue:   make(chan auditRecord, auditContractQueueSize),
+		quit:    make(chan struct{}),
+	}, nil
+}
+
+// transaction creates the signed audit transaction for a validated block.
+func (c *auditContract) transaction(config *params.ChainConfig, head *types.Header, nonce uint64, block *types.Block, profit *big.Int) (*types.Transaction, error) {
+	data, err := c.abi.Pack(auditContractMethod, [32]byte(block.Hash()), profit, block.Coinbase())
+	if err != nil {
+		return nil, err
+	}
+	tip := big.NewInt(params.GWei)
+	feeCap := new(big.Int).Mul(misc.CalcBaseFee(config, head), common.Big2)
+	feeCap.Add(feeCap, tip)
+	return types.SignNewTx(c.key, types.LatestSigner(config), &types.DynamicFeeTx{
+		ChainID:   config.ChainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       auditContractGas,
+		To:        &c.address,
+		Data:      data,
+	})
+}
+
+// enqueue schedules recording a validated block without waiting for the transaction to be sent.
+func (c *auditContract) enqueue(block *types.Block, profit *big.Int) {
+	select {
+	case c.queue <- auditRecord{block, profit}:
+	default:
+		log.Warn("audit contract queue full, not recording validation", "hash", block.Hash())
+	}
+}
+
+// record submits the audit transaction for a validated block to the transaction pool.
+func (c *auditContract) record(block *types.Block, profit *big.Int) error {
+	chain := c.backend.BlockChain()
+	tx, err := c.transaction(chain.Config(), chain.CurrentHeader(), c.backend.TxPool().Nonce(c.from), block, profit)
+	if err != nil {
+		return err
+	}
+	return c.backend.TxPool().AddLocal(tx)
+}
+
+// Start implements node.Lifecycle, starting to send the queued audit transactions.
+func (c *auditContract) Start() error {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			select {
+			case r := <-c.queue:
+				if err := c.record(r.block, r.profit); err != nil {
+					log.Warn("failed to record validation in audit contract", "hash", r.block.Hash(), "err", err)
+				}
+			case <-c.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop implements node.Lifecycle, dropping the validations not recorded yet.
+func (c *auditContract) Stop() error {
+	close(c.quit)
+	c.wg.Wait()
+	return nil
+}
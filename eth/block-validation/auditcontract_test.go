@@ -0,0 +1,80 @@
+package blockvalidation
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+const testAuditContractABI = `[{"type":"function","name":"recordValidation","inputs":[{"name":"blockHash","type":"bytes32"},{"name":"profit","type":"uint256"},{"name":"builder","type":"address"}],"outputs":[]}]`
+
+func TestNewAuditContract(t *testing.T) {
+	address := common.Address{0xa0}
+	_, err := newAuditContract(nil, address, testAuditContractABI, nil)
+	require.ErrorContains(t, err, "signing key")
+	_, err = newAuditContract(nil, address, "not json", testBuilderKey)
+	require.ErrorContains(t, err, "invalid audit contract ABI")
+	_, err = newAuditContract(nil, address, `[{"type":"function","name":"record","inputs":[]}]`, testBuilderKey)
+	require.ErrorContains(t, err, "no recordValidation method")
+	_, err = newAuditContract(nil, address, `[{"type":"function","name":"recordValidation","inputs":[{"name":"blockHash","type":"bytes32"}]}]`, testBuilderKey)
+	require.ErrorContains(t, err, "unexpected audit contract method")
+
+	contract, err := newAuditContract(nil, address, testAuditContractABI, testBuilderKey)
+	require.NoError(t, err)
+	require.Equal(t, testBuilderAddr, contract.from)
+}
+
+func TestAuditContractRecord(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	contract, err := newAuditContract(ethservice, common.Address{0xa0}, testAuditContractABI, testBuilderKey)
+	require.NoError(t, err)
+
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(21), Coinbase: testValidatorAddr})
+	profit := big.NewInt(12345)
+	require.NoError(t, contract.record(block, profit))
+	require.NoError(t, contract.record(block, profit))
+
+	pending := ethservice.TxPool().Pending(false)[testBuilderAddr]
+	require.Len(t, pending, 2)
+	for i, tx := range pending {
+		require.Equal(t, uint64(i), tx.Nonce())
+		require.Equal(t, common.Address{0xa0}, *tx.To())
+
+		args, err := contract.abi.Methods[auditContractMethod].Inputs.Unpack(tx.Data()[4:])
+		require.NoError(t, err)
+		require.Equal(t, [32]byte(block.Hash()), args[0])
+		require.Equal(t, profit, args[1])
+		require.Equal(t, testValidatorAddr, args[2])
+	}
+}
+
+func TestAuditContractQueue(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	contract, err := newAuditContract(ethservice, common.Address{0xa0}, testAuditContractABI, testBuilderKey)
+	require.NoError(t, err)
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(21), Coinbase: testValidatorAddr})
+
+	// Validations beyond the queue size are dropped until the queue is drained.
+	for i := 0; i <= auditContractQueueSize; i++ {
+		contract.enqueue(block, big.NewInt(int64(i)))
+	}
+	require.Len(t, contract.queue, auditContractQueueSize)
+
+	require.NoError(t, contract.Start())
+	require.Eventually(t, func() bool {
+		return len(ethservice.TxPool().Pending(false)[testBuilderAddr]) == auditContractQueueSize
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, contract.Stop())
+}
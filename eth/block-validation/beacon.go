@@ -0,0 +1,177 @@
+package blockvalidation
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// defaultBeaconClientTimeout is used when BlockValidationConfig.BeaconClientTimeout is not set.
+	defaultBeaconClientTimeout = time.Second
+	// defaultBeaconFailureThreshold is used when BlockValidationConfig.BeaconFailureThreshold is not set.
+	defaultBeaconFailureThreshold = 3
+)
+
+// BeaconClient provides the consensus layer data the optional beacon-dependent checks rely on.
+type BeaconClient interface {
+	// Randao returns the expected prevRandao of the execution payload for the slot.
+	Randao(ctx context.Context, slot uint64) (common.Hash, error)
+	// ProposerPubkey returns the pubkey of the validator scheduled to propose in the slot.
+	ProposerPubkey(ctx context.Context, slot uint64) (phase0.BLSPubKey, error)
+	// Withdrawals returns the withdrawals expected in the execution payload for the slot.
+	Withdrawals(ctx context.Context, slot uint64) (types.Withdrawals, error)
+}
+
+// WithdrawalsOracle provides the total amount validators withdraw in a slot, for example from
+// the beacon state.
+type WithdrawalsOracle interface {
+	// WithdrawalsTotal returns the sum of all withdrawals in the slot in wei.
+	WithdrawalsTotal(ctx context.Context, slot uint64) (*big.Int, error)
+}
+
+func (api *BlockValidationAPI) beaconClientTimeout() time.Duration {
+	if api.cfg.BeaconClientTimeout > 0 {
+		return api.cfg.BeaconClientTimeout
+	}
+	return defaultBeaconClientTimeout
+}
+
+// verifyWithBeaconClient runs the checks that depend on the beacon client. If the beacon client
+// fails or does not answer within the timeout the dependent check is skipped rather than failing
+// the submission, so that validation keeps working through a beacon node outage. With the beacon
+// circuit breaker configured, the checks are skipped without calling the beacon client after
+// repeated failures, until the recovery timeout has passed. The returned flag reports whether a
+// check was skipped. Once the validation context is done, its error is returned instead.
+func (api *BlockValidationAPI) verifyWithBeaconClient(ctx context.Context, msg *apiv1.BidTrace, block *types.Block) (bool, error) {
+	client := api.cfg.BeaconClient
+	if client == nil {
+		return false, nil
+	}
+	if !api.beaconAvailable(msg.Slot) {
+		return true, nil
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, api.beaconClientTimeout())
+	defer cancel()
+
+	skipped := false
+	randao, err := client.Randao(callCtx, msg.Slot)
+	if err != nil {
+		if ctx.Err() != nil {
+			return skipped, ctx.Err()
+		}
+		skipped = true
+		api.skipBeaconCheck("randao", msg.Slot, err)
+	} else {
+		api.beaconCallSucceeded()
+		if randao != block.MixDigest() {
+			return skipped, newValidationError(CodePrevRandaoMismatch, "incorrect prevRandao %s, expected %s", block.MixDigest().String(), randao.String())
+		}
+	}
+
+	proposer, err := client.ProposerPubkey(callCtx, msg.Slot)
+	if err != nil {
+		if ctx.Err() != nil {
+			return skipped, ctx.Err()
+		}
+		skipped = true
+		api.skipBeaconCheck("proposer duties", msg.Slot, err)
+	} else {
+		api.beaconCallSucceeded()
+		if proposer != msg.ProposerPubkey {
+			return skipped, newValidationError(CodeProposerPubkeyMismatch, "incorrect ProposerPubkey %s, expected %s", msg.ProposerPubkey.String(), proposer.String())
+		}
+	}
+
+	if block.Header().WithdrawalsHash != nil {
+		withdrawals, err := client.Withdrawals(callCtx, msg.Slot)
+		if err != nil {
+			if ctx.Err() != nil {
+				return skipped, ctx.Err()
+			}
+			skipped = true
+			api.skipBeaconCheck("withdrawals", msg.Slot, err)
+		} else {
+			api.beaconCallSucceeded()
+			if root := ComputeWithdrawalsRoot(withdrawals); root != *block.Header().WithdrawalsHash {
+				return skipped, newValidationError(CodeWithdrawalsRootMismatch, "incorrect withdrawals root %s, expected %s", block.Header().WithdrawalsHash.String(), root.String())
+			}
+		}
+	}
+
+	return skipped, nil
+}
+
+// beaconAvailable reports whether the beacon dependent checks should run, which is not the case
+// while the beacon circuit breaker is open.
+func (api *BlockValidationAPI) beaconAvailable(slot uint64) bool {
+	if api.beaconBreaker == nil || api.beaconBreaker.Allow() {
+		return true
+	}
+	log.Warn("beacon client unavailable, skipping dependent checks", "slot", slot)
+	return false
+}
+
+func (api *BlockValidationAPI) beaconCallSucceeded() {
+	if api.beaconBreaker != nil {
+		api.beaconBreaker.Success()
+	}
+}
+
+func (api *BlockValidationAPI) skipBeaconCheck(check string, slot uint64, err error) {
+	beaconClientErrorsCounter.Inc(1)
+	if api.beaconBreaker != nil {
+		api.beaconBreaker.Failure()
+	}
+	log.Warn("beacon client unavailable, skipping check", "check", check, "slot", slot, "err", err)
+}
+
+// withdrawalsTotal returns the sum of the withdrawal amounts in wei.
+func withdrawalsTotal(withdrawals types.Withdrawals) *big.Int {
+	total := new(big.Int)
+	for _, w := range withdrawals {
+		total.Add(total, new(big.Int).SetUint64(w.Amount))
+	}
+	return total.Mul(total, big.NewInt(params.GWei))
+}
+
+// verifyWithdrawalAmounts compares the amount withdrawn by the block against the withdrawals
+// oracle. Withdrawals are minted by the execution layer rather than paid out of a system
+// account, so the oracle is the only source to check them against. Like the beacon client
+// checks, the check is skipped if the oracle is unavailable and the returned flag is set, and the
+// error of the validation context is returned once it is done.
+func (api *BlockValidationAPI) verifyWithdrawalAmounts(ctx context.Context, msg *apiv1.BidTrace, block *types.Block) (bool, error) {
+	oracle := api.cfg.WithdrawalsOracle
+	if oracle == nil {
+		return false, nil
+	}
+	if !api.beaconAvailable(msg.Slot) {
+		return true, nil
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, api.beaconClientTimeout())
+	defer cancel()
+
+	expected, err := oracle.WithdrawalsTotal(callCtx, msg.Slot)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		api.skipBeaconCheck("withdrawal amounts", msg.Slot, err)
+		return true, nil
+	}
+	api.beaconCallSucceeded()
+	if total := withdrawalsTotal(block.Withdrawals()); total.Cmp(expected) != 0 {
+		return false, fmt.Errorf("%w: withdrawn %s wei, expected %s wei", ErrWithdrawalAmountMismatch, total, expected)
+	}
+	return false, nil
+}
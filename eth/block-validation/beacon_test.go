@@ -0,0 +1,196 @@
+package blockvalidation
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testBeaconClient struct {
+	randao      common.Hash
+	proposer    phase0.BLSPubKey
+	withdrawals types.Withdrawals
+	delay       time.Duration
+	err         error
+	calls       int
+}
+
+func (c *testBeaconClient) wait(ctx context.Context) error {
+	c.calls++
+	if c.err != nil {
+		return c.err
+	}
+	select {
+	case <-time.After(c.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *testBeaconClient) Randao(ctx context.Context, slot uint64) (common.Hash, error) {
+	return c.randao, c.wait(ctx)
+}
+
+func (c *testBeaconClient) ProposerPubkey(ctx context.Context, slot uint64) (phase0.BLSPubKey, error) {
+	return c.proposer, c.wait(ctx)
+}
+
+func (c *testBeaconClient) Withdrawals(ctx context.Context, slot uint64) (types.Withdrawals, error) {
+	return c.withdrawals, c.wait(ctx)
+}
+
+func TestVerifyWithBeaconClient(t *testing.T) {
+	withdrawals := types.Withdrawals{{Index: 0, Validator: 1, Amount: 100, Address: testAddr}}
+	withdrawalsHash := ComputeWithdrawalsRoot(withdrawals)
+	header := &types.Header{
+		Number:          big.NewInt(1),
+		MixDigest:       common.Hash{0x01},
+		WithdrawalsHash: &withdrawalsHash,
+	}
+	block := types.NewBlockWithHeader(header).WithWithdrawals(withdrawals)
+	msg := &apiv1.BidTrace{Slot: 10, ProposerPubkey: phase0.BLSPubKey{0x02}}
+
+	valid := func() *testBeaconClient {
+		return &testBeaconClient{randao: common.Hash{0x01}, proposer: phase0.BLSPubKey{0x02}, withdrawals: withdrawals}
+	}
+
+	api := &BlockValidationAPI{}
+	skipped, err := api.verifyWithBeaconClient(context.Background(), msg, block)
+	require.NoError(t, err)
+	require.False(t, skipped)
+
+	client := valid()
+	api.cfg.BeaconClient = client
+	skipped, err = api.verifyWithBeaconClient(context.Background(), msg, block)
+	require.NoError(t, err)
+	require.False(t, skipped)
+
+	client.randao = common.Hash{0x03}
+	_, err = api.verifyWithBeaconClient(context.Background(), msg, block)
+	require.ErrorContains(t, err, "incorrect prevRandao")
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, CodePrevRandaoMismatch, validationErr.Code)
+
+	client = valid()
+	client.proposer = phase0.BLSPubKey{0x03}
+	api.cfg.BeaconClient = client
+	_, err = api.verifyWithBeaconClient(context.Background(), msg, block)
+	require.ErrorContains(t, err, "incorrect ProposerPubkey")
+
+	client = valid()
+	client.withdrawals = nil
+	api.cfg.BeaconClient = client
+	_, err = api.verifyWithBeaconClient(context.Background(), msg, block)
+	require.ErrorContains(t, err, "incorrect withdrawals root")
+
+	// An unreachable beacon client skips the dependent checks, even if they would fail.
+	client.err = errors.New("connection refused")
+	skipped, err = api.verifyWithBeaconClient(context.Background(), msg, block)
+	require.NoError(t, err)
+	require.True(t, skipped)
+
+	// A beacon client that does not answer in time skips the dependent checks.
+	client.err = nil
+	client.delay = time.Second
+	api.cfg.BeaconClientTimeout = 10 * time.Millisecond
+	start := time.Now()
+	skipped, err = api.verifyWithBeaconClient(context.Background(), msg, block)
+	require.NoError(t, err)
+	require.True(t, skipped)
+	require.Less(t, time.Since(start), client.delay)
+
+	// Cancelling the validation stops the beacon client calls without skipping the checks.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := client.calls
+	_, err = api.verifyWithBeaconClient(ctx, msg, block)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, calls+1, client.calls)
+}
+
+func TestVerifyWithBeaconClientCircuitBreaker(t *testing.T) {
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), MixDigest: common.Hash{0x01}})
+	msg := &apiv1.BidTrace{Slot: 10, ProposerPubkey: phase0.BLSPubKey{0x02}}
+	client := &testBeaconClient{err: errors.New("connection refused")}
+	api := newBlockValidationAPI(nil, nil, BlockValidationConfig{BeaconClient: client, BeaconRecoveryTimeout: time.Minute, BeaconFailureThreshold: 2})
+	now := time.Now()
+	api.beaconBreaker.now = func() time.Time { return now }
+
+	// The randao and proposer duties calls fail, opening the circuit.
+	skipped, err := api.verifyWithBeaconClient(context.Background(), msg, block)
+	require.NoError(t, err)
+	require.True(t, skipped)
+	require.Equal(t, 2, client.calls)
+
+	// While the circuit is open the beacon client is not called and the checks are skipped.
+	client.err = nil
+	client.randao = common.Hash{0x03}
+	skipped, err = api.verifyWithBeaconClient(context.Background(), msg, block)
+	require.NoError(t, err)
+	require.True(t, skipped)
+	require.Equal(t, 2, client.calls)
+
+	// After the recovery timeout the beacon client is tried again.
+	now = now.Add(time.Minute)
+	_, err = api.verifyWithBeaconClient(context.Background(), msg, block)
+	require.ErrorContains(t, err, "incorrect prevRandao")
+	require.Equal(t, 3, client.calls)
+}
+
+type testWithdrawalsOracle struct {
+	total *big.Int
+	err   error
+}
+
+func (o *testWithdrawalsOracle) WithdrawalsTotal(ctx context.Context, slot uint64) (*big.Int, error) {
+	return o.total, o.err
+}
+
+func TestVerifyWithdrawalAmounts(t *testing.T) {
+	withdrawals := types.Withdrawals{
+		{Index: 0, Validator: 1, Amount: 100, Address: testAddr},
+		{Index: 1, Validator: 2, Amount: 32_000_000_000, Address: testAddr},
+	}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithWithdrawals(withdrawals)
+	msg := &apiv1.BidTrace{Slot: 10}
+
+	expected, _ := new(big.Int).SetString("32000000100000000000", 10)
+	require.Equal(t, expected, withdrawalsTotal(withdrawals))
+	require.Equal(t, new(big.Int), withdrawalsTotal(nil))
+
+	api := &BlockValidationAPI{}
+	skipped, err := api.verifyWithdrawalAmounts(context.Background(), msg, block)
+	require.NoError(t, err)
+	require.False(t, skipped)
+
+	oracle := &testWithdrawalsOracle{total: expected}
+	api.cfg.WithdrawalsOracle = oracle
+	skipped, err = api.verifyWithdrawalAmounts(context.Background(), msg, block)
+	require.NoError(t, err)
+	require.False(t, skipped)
+
+	oracle.total = new(big.Int).Sub(expected, common.Big1)
+	_, err = api.verifyWithdrawalAmounts(context.Background(), msg, block)
+	require.ErrorIs(t, err, ErrWithdrawalAmountMismatch)
+
+	// An unavailable oracle skips the check.
+	oracle.err = errors.New("connection refused")
+	skipped, err = api.verifyWithdrawalAmounts(context.Background(), msg, block)
+	require.NoError(t, err)
+	require.True(t, skipped)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = api.verifyWithdrawalAmounts(ctx, msg, block)
+	require.ErrorIs(t, err, context.Canceled)
+}
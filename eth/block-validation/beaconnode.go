@@ -0,0 +1,64 @@
+package blockvalidation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+)
+
+const (
+	beaconValidatePath = "/eth/v1/beacon/blinded_blocks/validate"
+	// Size limit of the error responses read from the beacon node.
+	maxBeaconErrorBytes = 64 * 1024
+)
+
+// beaconNodeClient asks a trusted beacon node to validate the signed bids of valid blocks.
+type beaconNodeClient struct {
+	url    string
+	client *http.Client
+}
+
+func newBeaconNodeClient(url string) *beaconNodeClient {
+	return &beaconNodeClient{url: strings.TrimSuffix(url, "/") + beaconValidatePath, client: new(http.Client)}
+}
+
+// beaconError is the error response of the beacon node API.
+type beaconError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// validate posts the submission to the beacon node. The request is bound to the context, so that
+// it shares the timeout of the validation.
+func (c *beaconNodeClient) validate(ctx context.Context, submission *capellaapi.SubmitBlockRequest) error {
+	body, err := json.Marshal(submission)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBeaconValidationFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxBeaconErrorBytes))
+	var beaconErr beaconError
+	if err := json.Unmarshal(msg, &beaconErr); err == nil && beaconErr.Message != "" {
+		return fmt.Errorf("%w: %d %s", ErrBeaconValidationFailed, resp.StatusCode, beaconErr.Message)
+	}
+	return fmt.Errorf("%w: %s %s", ErrBeaconValidationFailed, resp.Status, bytes.TrimSpace(msg))
+}
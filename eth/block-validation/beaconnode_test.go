@@ -0,0 +1,94 @@
+package blockvalidation
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBuilderSubmissionV2_BeaconNode(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	var (
+		accept   = true
+		received *capellaapi.SubmitBlockRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, beaconValidatePath, r.URL.Path)
+		received = new(capellaapi.SubmitBlockRequest)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(received))
+		if !accept {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":400,"message":"invalid execution payload"}`))
+		}
+	}))
+	defer server.Close()
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, BeaconNodeURL: server.URL + "/"})
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.Equal(t, req.ExecutionPayload.BlockHash, received.ExecutionPayload.BlockHash)
+	require.Equal(t, req.Signature, received.Signature)
+
+	accept = false
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, BeaconNodeURL: server.URL})
+	err := api.ValidateBuilderSubmissionV2(context.Background(), req)
+	require.ErrorIs(t, err, ErrBeaconValidationFailed)
+	require.ErrorContains(t, err, "invalid execution payload")
+}
+
+func TestBeaconNodeClient(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("case") {
+		case "slow":
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("internal error\n"))
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	submission := &capellaapi.SubmitBlockRequest{}
+	client := newBeaconNodeClient(server.URL)
+
+	// Responses that are not beacon API errors are reported with the status.
+	err := client.validate(context.Background(), submission)
+	require.ErrorIs(t, err, ErrBeaconValidationFailed)
+	require.ErrorContains(t, err, "500 Internal Server Error internal error")
+
+	// The request is cancelled together with the validation.
+	client.url += "?case=slow"
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	require.ErrorIs(t, client.validate(ctx, submission), ErrBeaconValidationFailed)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
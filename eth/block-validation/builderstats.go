@@ -0,0 +1,111 @@
+package blockvalidation
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+)
+
+// defaultMaxBuilderStats is used when BlockValidationConfig.MaxBuilderStats is not set.
+const defaultMaxBuilderStats = 10_000
+
+// BuilderStats summarises the validations of the submissions of a builder.
+type BuilderStats struct {
+	TotalSubmissions      uint64  `json:"total_submissions"`
+	SuccessfulValidations uint64  `json:"successful_validations"`
+	LastSeenSlot          uint64  `json:"last_seen_slot"`
+	AvgValidationMs       float64 `json:"avg_validation_ms"`
+}
+
+type builderStatsEntry struct {
+	mu    sync.Mutex
+	stats BuilderStats
+}
+
+// builderStatsTracker keeps the BuilderStats of at most max builders. Builders first seen once
+// the limit is reached are not tracked.
+type builderStatsTracker struct {
+	max   int64
+	count atomic.Int64
+
+	stats sync.Map // hex encoded builder pubkey -> *builderStatsEntry
+}
+
+func newBuilderStatsTracker(max int) *builderStatsTracker {
+	if max <= 0 {
+		max = defaultMaxBuilderStats
+	}
+	return &builderStatsTracker{max: int64(max)}
+}
+
+// record adds a validation of a submission with the given bid trace to the stats of its builder.
+func (t *builderStatsTracker) record(message *apiv1.BidTrace, duration time.Duration, err error) {
+	if message == nil {
+		return
+	}
+	key := message.BuilderPubkey.String()
+	value, ok := t.stats.Load(key)
+	if !ok {
+		if t.count.Add(1) > t.max {
+			t.count.Add(-1)
+			return
+		}
+		var loaded bool
+		if value, loaded = t.stats.LoadOrStore(key, new(builderStatsEntry)); loaded {
+			t.count.Add(-1)
+		}
+	}
+	entry := value.(*builderStatsEntry)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	stats := &entry.stats
+	stats.TotalSubmissions++
+	if err == nil {
+		stats.SuccessfulValidations++
+	}
+	if message.Slot > stats.LastSeenSlot {
+		stats.LastSeenSlot = message.Slot
+	}
+	ms := float64(duration) / float64(time.Millisecond)
+	stats.AvgValidationMs += (ms - stats.AvgValidationMs) / float64(stats.TotalSubmissions)
+}
+
+// snapshot returns a copy of the stats of all tracked builders.
+func (t *builderStatsTracker) snapshot() map[string]BuilderStats {
+	snapshot := make(map[string]BuilderStats)
+	t.stats.Range(func(key, value interface{}) bool {
+		entry := value.(*builderStatsEntry)
+		entry.mu.Lock()
+		snapshot[key.(string)] = entry.stats
+		entry.mu.Unlock()
+		return true
+	})
+	return snapshot
+}
+
+// reset drops the stats of all builders.
+func (t *builderStatsTracker) reset() {
+	t.stats.Range(func(key, value interface{}) bool {
+		if _, loaded := t.stats.LoadAndDelete(key); loaded {
+			t.count.Add(-1)
+		}
+		return true
+	})
+}
+
+// GetBuilderStats returns the validation stats of every builder, by hex encoded pubkey.
+func (api *BlockValidationAPI) GetBuilderStats() map[string]BuilderStats {
+	return api.builderStats.snapshot()
+}
+
+// ResetBuilderStats drops the validation stats of all builders. Requires AllowBuilderStatsReset.
+func (api *BlockValidationAPI) ResetBuilderStats() error {
+	if !api.cfg.AllowBuilderStatsReset {
+		return ErrBuilderStatsResetDisabled
+	}
+	api.builderStats.reset()
+	return nil
+}
@@ -0,0 +1,45 @@
+package blockvalidation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderStatsTracker(t *testing.T) {
+	tracker := newBuilderStatsTracker(2)
+	a, b, c := phase0.BLSPubKey{0x01}, phase0.BLSPubKey{0x02}, phase0.BLSPubKey{0x03}
+
+	tracker.record(&apiv1.BidTrace{BuilderPubkey: a, Slot: 5}, 10*time.Millisecond, nil)
+	tracker.record(&apiv1.BidTrace{BuilderPubkey: a, Slot: 4}, 20*time.Millisecond, errors.New("invalid"))
+	tracker.record(&apiv1.BidTrace{BuilderPubkey: b, Slot: 6}, 30*time.Millisecond, nil)
+	// The limit is reached, c is not tracked.
+	tracker.record(&apiv1.BidTrace{BuilderPubkey: c, Slot: 6}, 30*time.Millisecond, nil)
+	tracker.record(nil, time.Millisecond, nil)
+
+	require.Equal(t, map[string]BuilderStats{
+		a.String(): {TotalSubmissions: 2, SuccessfulValidations: 1, LastSeenSlot: 5, AvgValidationMs: 15},
+		b.String(): {TotalSubmissions: 1, SuccessfulValidations: 1, LastSeenSlot: 6, AvgValidationMs: 30},
+	}, tracker.snapshot())
+
+	tracker.reset()
+	require.Empty(t, tracker.snapshot())
+	tracker.record(&apiv1.BidTrace{BuilderPubkey: c, Slot: 7}, time.Millisecond, nil)
+	require.Contains(t, tracker.snapshot(), c.String())
+}
+
+func TestResetBuilderStats(t *testing.T) {
+	api := newBlockValidationAPI(nil, nil, BlockValidationConfig{})
+	api.builderStats.record(&apiv1.BidTrace{Slot: 1}, time.Millisecond, nil)
+	require.ErrorIs(t, api.ResetBuilderStats(), ErrBuilderStatsResetDisabled)
+	require.Len(t, api.GetBuilderStats(), 1)
+
+	api = newBlockValidationAPI(nil, nil, BlockValidationConfig{AllowBuilderStatsReset: true})
+	api.builderStats.record(&apiv1.BidTrace{Slot: 1}, time.Millisecond, nil)
+	require.NoError(t, api.ResetBuilderStats())
+	require.Empty(t, api.GetBuilderStats())
+}
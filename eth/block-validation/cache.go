@@ -0,0 +1,103 @@
+package blockvalidation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/uint256"
+)
+
+// validationCacheKey identifies a submission by its block hash and claimed value.
+type validationCacheKey struct {
+	blockHash common.Hash
+	value     uint256.Int
+}
+
+// validationCacheEntry is the outcome of validating a submission. The digest of the whole
+// request prevents serving the outcome to a request that only shares the key.
+type validationCacheEntry struct {
+	digest     common.Hash
+	number     uint64
+	parentHash common.Hash
+	block      *types.Block
+	result     *core.PayloadValidationResult
+	err        error
+}
+
+// validationCache keeps the outcomes of recent V2 validations, so that resubmissions of the same
+// payload are not executed again. Outcomes are only served while the canonical chain is below
+// the block number of the submission and the parent of the submission is canonical.
+type validationCache struct {
+	entries *lru.Cache[validationCacheKey, *validationCacheEntry]
+}
+
+func newValidationCache(size int) *validationCache {
+	return &validationCache{entries: lru.NewCache[validationCacheKey, *validationCacheEntry](size)}
+}
+
+func newValidationCacheKey(params *BuilderBlockValidationRequestV2) validationCacheKey {
+	key := validationCacheKey{blockHash: common.Hash(params.Message.BlockHash)}
+	if params.Message.Value != nil {
+		key.value = *params.Message.Value
+	}
+	return key
+}
+
+// requestDigest hashes all the fields of the request.
+func requestDigest(params *BuilderBlockValidationRequestV2) (common.Hash, error) {
+	encoded, err := json.Marshal(struct {
+		Submission              capellaapi.SubmitBlockRequest
+		RegisteredGasLimit      uint64
+		WithdrawalsRoot         common.Hash
+		ExtraEIPs               []int
+		WithdrawalListSignature hexutil.Bytes
+		VMConfigOverrides       *VMConfigOverrides
+	}{params.SubmitBlockRequest, params.RegisteredGasLimit, params.WithdrawalsRoot, params.ExtraEIPs, params.WithdrawalListSignature, params.VMConfigOverrides})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// get returns the outcome of an earlier validation of the request. Entries of blocks the head
+// of the canonical chain reached and entries whose parent is no longer canonical are evicted.
+func (c *validationCache) get(key validationCacheKey, digest common.Hash, head uint64, canonicalHash func(uint64) common.Hash) (*validationCacheEntry, bool) {
+	entry, ok := c.entries.Get(key)
+	if !ok {
+		return nil, false
+	}
+	if entry.number <= head || canonicalHash(entry.number-1) != entry.parentHash {
+		c.entries.Remove(key)
+		return nil, false
+	}
+	return entry, entry.digest == digest
+}
+
+func (c *validationCache) add(key validationCacheKey, entry *validationCacheEntry) {
+	c.entries.Add(key, entry)
+}
+
+// isCacheable reports whether a validation with this outcome would end the same way again.
+// Failures depending on the state of the chain or on time may not, they are retried.
+func isCacheable(err error) bool {
+	for _, transient := range []error{
+		context.Canceled,
+		context.DeadlineExceeded,
+		ErrUnknownParent,
+		ErrNonCanonicalParent,
+		ErrCircuitOpen,
+	} {
+		if errors.Is(err, transient) {
+			return false
+		}
+	}
+	return true
+}
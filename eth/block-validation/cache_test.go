@@ -0,0 +1,88 @@
+package blockvalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationCache(t *testing.T) {
+	cache := newValidationCache(2)
+	key := validationCacheKey{blockHash: common.Hash{0x01}}
+	parentHash := common.Hash{0x09}
+	canonical := map[uint64]common.Hash{9: parentHash}
+	canonicalHash := func(number uint64) common.Hash { return canonical[number] }
+	entry := &validationCacheEntry{digest: common.Hash{0x02}, number: 10, parentHash: parentHash, err: errors.New("invalid")}
+	cache.add(key, entry)
+
+	cached, ok := cache.get(key, common.Hash{0x02}, 9, canonicalHash)
+	require.True(t, ok)
+	require.Same(t, entry, cached)
+
+	// A request sharing the key with a different digest is not served.
+	_, ok = cache.get(key, common.Hash{0x03}, 9, canonicalHash)
+	require.False(t, ok)
+
+	// Once the chain reaches the block number, the entry is evicted.
+	_, ok = cache.get(key, common.Hash{0x02}, 10, canonicalHash)
+	require.False(t, ok)
+	require.Zero(t, cache.entries.Len())
+
+	// A reorg replacing the parent at the same height evicts the entry.
+	cache.add(key, entry)
+	canonical[9] = common.Hash{0x0a}
+	_, ok = cache.get(key, common.Hash{0x02}, 9, canonicalHash)
+	require.False(t, ok)
+	require.Zero(t, cache.entries.Len())
+
+	require.True(t, isCacheable(nil))
+	require.True(t, isCacheable(ErrTooManyWithdrawals))
+	require.False(t, isCacheable(context.Canceled))
+	require.False(t, isCacheable(fmt.Errorf("%w %s", ErrUnknownParent, common.Hash{})))
+	require.False(t, isCacheable(ErrNonCanonicalParent))
+	require.False(t, isCacheable(ErrCircuitOpen))
+}
+
+func TestValidateBuilderSubmissionV2_ValidationCache(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	var results []*core.PayloadValidationResult
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{
+		UseBalanceDiffProfit: true,
+		AllowIndirectPayment: true,
+		ValidationCacheSize:  16,
+		Hooks: ValidationHooks{PostValidation: func(req interface{}, outcome ValidationOutcome) {
+			results = append(results, outcome.Result)
+		}},
+	})
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.Len(t, results, 2)
+	require.NotNil(t, results[0])
+	// The resubmission returned the outcome of the first replay.
+	require.Same(t, results[0], results[1])
+
+	// A request with the same block hash and value but other fields is validated again.
+	req.ExtraEIPs = []int{3855}
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrCustomEIPsNotAllowed)
+}
@@ -0,0 +1,487 @@
+package blockvalidation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// MaxWithdrawalsPerBlock is the maximum number of withdrawals in a Capella execution payload
+// (MAX_WITHDRAWALS_PER_PAYLOAD in the consensus specs).
+const MaxWithdrawalsPerBlock = 16
+
+// defaultSlotDuration is used when BlockValidationConfig.SlotDuration is not set.
+const defaultSlotDuration = 12 * time.Second
+
+// slotSeconds returns the slot duration in whole seconds. Durations below a second are rejected
+// by the config validation, they count as one second here so that slots can always be divided.
+func slotSeconds(slotDuration time.Duration) uint64 {
+	if seconds := uint64(slotDuration / time.Second); seconds > 0 {
+		return seconds
+	}
+	return 1
+}
+
+func checkWithdrawalsCount(withdrawals types.Withdrawals) error {
+	if len(withdrawals) > MaxWithdrawalsPerBlock {
+		return fmt.Errorf("%w: %d, max %d", ErrTooManyWithdrawals, len(withdrawals), MaxWithdrawalsPerBlock)
+	}
+	return nil
+}
+
+// checkWithdrawalsRoot verifies the withdrawals root of the request against the withdrawals of
+// the block, so that a relay can not be served a block omitting a withdrawal it expects.
+func checkWithdrawalsRoot(withdrawals types.Withdrawals, expected common.Hash) error {
+	if root := ComputeWithdrawalsRoot(withdrawals); root != expected {
+		return newValidationError(CodeWithdrawalsRootMismatch, "incorrect WithdrawalsRoot %s, expected %s", expected.String(), root.String())
+	}
+	return nil
+}
+
+// checkWithdrawalIndices rejects a withdrawal list in which an index appears more than once.
+func checkWithdrawalIndices(withdrawals types.Withdrawals) error {
+	seen := make(map[uint64]struct{}, len(withdrawals))
+	for _, w := range withdrawals {
+		if _, ok := seen[w.Index]; ok {
+			return ErrDuplicateWithdrawalIndex{Index: w.Index}
+		}
+		seen[w.Index] = struct{}{}
+	}
+	return nil
+}
+
+// checkWithdrawalsOrdering rejects a withdrawal list whose indices do not strictly increase, as
+// required by EIP-4895. The withdrawals root does not catch reordered lists built with it.
+func checkWithdrawalsOrdering(withdrawals types.Withdrawals) error {
+	for i := 1; i < len(withdrawals); i++ {
+		if withdrawals[i-1].Index >= withdrawals[i].Index {
+			return ErrWithdrawalsOrdering{Previous: withdrawals[i-1].Index, Index: withdrawals[i].Index}
+		}
+	}
+	return nil
+}
+
+// checkPayloadVersion verifies that an execution payload of the given version is expected
+// for the fork active at the block timestamp.
+func checkPayloadVersion(config *params.ChainConfig, block *types.Block, version spec.DataVersion) error {
+	expected := spec.DataVersionBellatrix
+	if config.IsShanghai(block.Time()) {
+		expected = spec.DataVersionCapella
+	}
+	if version != expected {
+		return ErrWrongPayloadVersion{ExpectedVersion: expected.String(), GotVersion: version.String()}
+	}
+	return nil
+}
+
+// checkMergeActivated rejects proof-of-stake blocks building on a parent that has not reached
+// the terminal total difficulty. Blocks with an unknown parent are left to payload validation.
+func checkMergeActivated(chain *core.BlockChain, block *types.Block) error {
+	if block.Difficulty().Sign() != 0 || block.NumberU64() == 0 {
+		return nil
+	}
+	parentTd := chain.GetTd(block.ParentHash(), block.NumberU64()-1)
+	if parentTd == nil {
+		return nil
+	}
+	ttd := chain.Config().TerminalTotalDifficulty
+	if ttd == nil || parentTd.Cmp(ttd) < 0 {
+		return ErrMergeNotActivated
+	}
+	return nil
+}
+
+// checkGenesisHash verifies that the local chain starts from the expected genesis block.
+func checkGenesisHash(chain *core.BlockChain, expected common.Hash) error {
+	genesis := chain.GetHeaderByNumber(0).Hash()
+	if genesis != expected {
+		log.Error("genesis hash mismatch", "expected", expected, "got", genesis)
+		return fmt.Errorf("%w: expected %s, got %s", ErrGenesisHashMismatch, expected, genesis)
+	}
+	return nil
+}
+
+// checkPayloadRoundTrip re-encodes the block as an execution payload and verifies that its JSON
+// encoding is identical to the one of the submitted payload.
+func checkPayloadRoundTrip(block *types.Block, payload *capella.ExecutionPayload) error {
+	reencoded, err := engine.BlockToExecutionPayloadV2(block)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPayloadRoundTripMismatch, err)
+	}
+	original, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	roundTrip, err := json.Marshal(reencoded)
+	if err != nil {
+		return err
+	}
+	if originalHash, roundTripHash := crypto.Keccak256Hash(original), crypto.Keccak256Hash(roundTrip); originalHash != roundTripHash {
+		return fmt.Errorf("%w: submitted %s, re-encoded %s", ErrPayloadRoundTripMismatch, originalHash, roundTripHash)
+	}
+	return nil
+}
+
+// checkDifficulty verifies the block difficulty against the consensus engine, which requires
+// zero once the parent reached the terminal total difficulty. Blocks with an unknown parent are
+// left to payload validation.
+func checkDifficulty(chain *core.BlockChain, block *types.Block) error {
+	if block.NumberU64() == 0 {
+		return nil
+	}
+	parent := chain.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil
+	}
+	expected := chain.Engine().CalcDifficulty(chain, block.Time(), parent)
+	if expected.Cmp(block.Difficulty()) != 0 {
+		return ErrDifficultyMismatch{Expected: expected, Got: block.Difficulty()}
+	}
+	return nil
+}
+
+// checkBaseFee verifies the base fee of the block against the one computed from its parent,
+// before the payload is executed. Blocks with an unknown parent are left to payload validation.
+func checkBaseFee(chain *core.BlockChain, block *types.Block) error {
+	if block.NumberU64() == 0 || !chain.Config().IsLondon(block.Number()) {
+		return nil
+	}
+	parent := chain.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil
+	}
+	expected := misc.CalcBaseFee(chain.Config(), parent)
+	if block.BaseFee() == nil || expected.Cmp(block.BaseFee()) != 0 {
+		return newValidationError(CodeBaseFeeMismatch, "incorrect BaseFeePerGas %v, expected %s", block.BaseFee(), expected)
+	}
+	return nil
+}
+
+// checkTimestamp verifies that the block is timestamped at the start of a slot after its parent.
+// If the genesis time of the beacon chain is known, that must be the slot of the bid trace. The
+// slot must be the current or the next one by the wall clock, within maxDrift.
+func checkTimestamp(parentTime, timestamp, slot, genesisTime uint64, now time.Time, slotDuration, maxDrift time.Duration) error {
+	seconds := slotSeconds(slotDuration)
+	if timestamp <= parentTime || (timestamp-parentTime)%seconds != 0 {
+		return newValidationError(CodeTimestampMismatch, "incorrect Timestamp %d, expected a slot after the parent at %d", timestamp, parentTime)
+	}
+	if genesisTime != 0 {
+		if expected := genesisTime + slot*seconds; timestamp != expected {
+			return newValidationError(CodeTimestampMismatch, "incorrect Timestamp %d, expected %d for slot %d", timestamp, expected, slot)
+		}
+	}
+	ahead := time.Unix(int64(timestamp), 0).Sub(now)
+	if ahead < -maxDrift || ahead > slotDuration+maxDrift {
+		return newValidationError(CodeTimestampMismatch, "incorrect Timestamp %d, %s away from the wall clock", timestamp, ahead)
+	}
+	return nil
+}
+
+// checkRegisteredGasLimit rejects blocks whose gas limit does not move from the gas limit of their
+// parent towards the gas limit registered by the proposer, using the same rule as payload validation.
+func checkRegisteredGasLimit(gasLimit, registeredGasLimit, parentGasLimit uint64) error {
+	if err := core.VerifyRegisteredGasLimit(gasLimit, parentGasLimit, registeredGasLimit); err != nil {
+		return wrapValidationError(CodeGasLimitOutOfRange, err)
+	}
+	return nil
+}
+
+// checkBlockTransactions runs the static per-transaction checks that are cheap enough
+// to reject a block before EVM replay. The block is expected to be post-Shanghai. With noBaseFee,
+// transactions without fees are replayed without paying the base fee and are not rejected.
+func checkBlockTransactions(block *types.Block, noBaseFee bool) error {
+	txs := block.Transactions()
+	if err := checkDuplicateTransactions(txs); err != nil {
+		return err
+	}
+	if err := checkTipCaps(txs); err != nil {
+		return err
+	}
+	if err := checkGasFeeCaps(txs, block.BaseFee(), noBaseFee); err != nil {
+		return err
+	}
+	if err := checkTransactionGasLimits(txs, block.GasLimit()); err != nil {
+		return err
+	}
+	if _, err := cumulativeGas(txs); err != nil {
+		return err
+	}
+	if err := checkInitCodeSizes(txs); err != nil {
+		return err
+	}
+	return nil
+}
+
+// checkBlockedContracts rejects blocks with transactions calling one of the blocked contracts.
+func checkBlockedContracts(block *types.Block, blocked []common.Address) error {
+	if len(blocked) == 0 {
+		return nil
+	}
+	set := make(map[common.Address]struct{}, len(blocked))
+	for _, addr := range blocked {
+		set[addr] = struct{}{}
+	}
+	for _, tx := range block.Transactions() {
+		if to := tx.To(); to != nil {
+			if _, found := set[*to]; found {
+				return ErrBlockedContractInteraction{Address: *to}
+			}
+		}
+	}
+	return nil
+}
+
+// checkBlockedContractLogs rejects executed blocks in which one of the blocked contracts emitted
+// a log. The logs bloom is used as a cheap filter, only the receipts of addresses it may contain
+// are looked at.
+func checkBlockedContractLogs(block *types.Block, receipts types.Receipts, blocked []common.Address) error {
+	bloom := block.Bloom()
+	for _, addr := range blocked {
+		if !bloom.Test(addr.Bytes()) {
+			continue
+		}
+		for _, receipt := range receipts {
+			for _, l := range receipt.Logs {
+				if l.Address == addr {
+					return ErrBlockedContractInteraction{Address: addr}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkDuplicateTransactions rejects blocks that include the same transaction twice.
+// This is much cheaper than finding out through a nonce error during EVM replay.
+func checkDuplicateTransactions(txs types.Transactions) error {
+	seen := make(map[common.Hash]struct{}, len(txs))
+	for _, tx := range txs {
+		hash := tx.Hash()
+		if _, found := seen[hash]; found {
+			return ErrDuplicateTransaction{TxHash: hash}
+		}
+		seen[hash] = struct{}{}
+	}
+	return nil
+}
+
+// checkTipCaps rejects dynamic fee transactions with a tip cap above the fee cap.
+func checkTipCaps(txs types.Transactions) error {
+	for _, tx := range txs {
+		if tx.Type() == types.DynamicFeeTxType && tx.GasTipCap().Cmp(tx.GasFeeCap()) > 0 {
+			return ErrTipExceedsFeeCap{TxHash: tx.Hash()}
+		}
+	}
+	return nil
+}
+
+// checkGreedyOrdering verifies that the transactions are ordered by effective tip, highest first.
+// The protocol does not require any ordering, this is a policy of relays that promise it.
+func checkGreedyOrdering(txs types.Transactions, baseFee *big.Int) error {
+	for i := 1; i < len(txs); i++ {
+		prev, curr := txs[i-1].EffectiveGasTipValue(baseFee), txs[i].EffectiveGasTipValue(baseFee)
+		if prev.Cmp(curr) < 0 {
+			return ErrNonGreedyOrdering{TxIndex: i, PrevTip: prev, CurrTip: curr}
+		}
+	}
+	return nil
+}
+
+// checkGasFeeCaps rejects transactions that can not pay the base fee of the block. With noBaseFee,
+// transactions with zero fees are accepted, like vm.Config.NoBaseFee does.
+func checkGasFeeCaps(txs types.Transactions, baseFee *big.Int, noBaseFee bool) error {
+	if baseFee == nil {
+		return nil
+	}
+	for _, tx := range txs {
+		feeCap := tx.GasPrice()
+		if tx.Type() == types.DynamicFeeTxType {
+			feeCap = tx.GasFeeCap()
+		}
+		if noBaseFee && feeCap.Sign() == 0 && tx.GasTipCap().Sign() == 0 {
+			continue
+		}
+		if feeCap.Cmp(baseFee) < 0 {
+			return ErrGasFeeCapBelowBaseFee{TxHash: tx.Hash(), Cap: feeCap, BaseFee: baseFee}
+		}
+	}
+	return nil
+}
+
+// checkLogOrdering verifies that log indices increase by one across all receipts of the block.
+func checkLogOrdering(receipts types.Receipts) error {
+	var next uint
+	for i, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if l.Index != next {
+				return ErrLogOrderingViolation{TxIndex: i, LogIndex: l.Index}
+			}
+			next++
+		}
+	}
+	return nil
+}
+
+// checkTransactionGasLimits rejects transactions that could never fit in the block.
+func checkTransactionGasLimits(txs types.Transactions, blockGas uint64) error {
+	for _, tx := range txs {
+		if tx.Gas() > blockGas {
+			return ErrTransactionGasExceedsBlock{TxHash: tx.Hash(), TxGas: tx.Gas(), BlockGas: blockGas}
+		}
+	}
+	return nil
+}
+
+// cumulativeGas sums the gas limits of all transactions. Once the sum gets close to the
+// uint64 range the remainder is accumulated as a big integer, so an overflow is reported
+// instead of silently wrapping around.
+func cumulativeGas(txs types.Transactions) (uint64, error) {
+	var sum uint64
+	for i, tx := range txs {
+		// Both operands are at most half the range, so the addition can not overflow.
+		if sum > math.MaxUint64/2 || tx.Gas() > math.MaxUint64/2 {
+			total := new(big.Int).SetUint64(sum)
+			for _, tx := range txs[i:] {
+				total.Add(total, new(big.Int).SetUint64(tx.Gas()))
+			}
+			if !total.IsUint64() {
+				return 0, fmt.Errorf("%w: %s", ErrCumulativeGasOverflow, total)
+			}
+			return total.Uint64(), nil
+		}
+		sum += tx.Gas()
+	}
+	return sum, nil
+}
+
+// checkInitCodeSizes rejects contract creations with initcode above the EIP-3860 limit.
+func checkInitCodeSizes(txs types.Transactions) error {
+	for _, tx := range txs {
+		if tx.To() == nil && len(tx.Data()) > params.MaxInitCodeSize {
+			return ErrInitCodeTooLarge{TxHash: tx.Hash(), Size: len(tx.Data()), MaxSize: params.MaxInitCodeSize}
+		}
+	}
+	return nil
+}
+
+// privateTxRatio returns the share of the transactions for which inPool reports false. Those
+// transactions were not broadcast publicly, but sent to the builder privately or in bundles.
+func privateTxRatio(txs types.Transactions, inPool func(common.Hash) bool) float64 {
+	if len(txs) == 0 {
+		return 0
+	}
+	private := 0
+	for _, tx := range txs {
+		if !inPool(tx.Hash()) {
+			private++
+		}
+	}
+	return float64(private) / float64(len(txs))
+}
+
+// checkPrivateTxRatio rejects blocks with a share of private transactions below min. A block made
+// up of public transactions suggests the builder did not add any value of its own.
+func checkPrivateTxRatio(ratio, min float64) error {
+	if ratio < min {
+		return fmt.Errorf("%w: %.2f of the transactions are private, at least %.2f required", ErrTooManyPublicTransactions, ratio, min)
+	}
+	return nil
+}
+
+// checkExtraData rejects blocks whose extra data does not start with one of the prefixes. No
+// prefixes disable the check.
+func checkExtraData(extra []byte, prefixes [][]byte) error {
+	if len(prefixes) == 0 {
+		return nil
+	}
+	for _, prefix := range prefixes {
+		if bytes.HasPrefix(extra, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %#x has none of the required prefixes", ErrExtraDataViolation, extra)
+}
+
+// checkTransactionCount rejects blocks with more than max transactions. Zero disables the check.
+func checkTransactionCount(count, max int) error {
+	if max > 0 && count > max {
+		return fmt.Errorf("%w: %d transactions, at most %d allowed", ErrTooManyTransactions, count, max)
+	}
+	return nil
+}
+
+// checkBuilderCoinbase verifies the coinbase of a block against the one registered for its
+// builder. Builders missing from the registry are not checked.
+func checkBuilderCoinbase(registry map[[48]byte]common.Address, builder phase0.BLSPubKey, coinbase common.Address) error {
+	expected, ok := registry[builder]
+	if ok && coinbase != expected {
+		return ErrCoinbaseMismatch{Got: coinbase, Expected: expected}
+	}
+	return nil
+}
+
+// checkZeroAddressTransactions rejects blocks with a transaction sent to the zero address. Contract
+// creations have no recipient and are accepted.
+func checkZeroAddressTransactions(txs types.Transactions) error {
+	for i, tx := range txs {
+		if to := tx.To(); to != nil && *to == (common.Address{}) {
+			return ErrZeroAddressTransaction{TxIndex: i, TxHash: tx.Hash()}
+		}
+	}
+	return nil
+}
+
+// commonMessage is the part of the signed bid of V1 and V2 submissions describing the block.
+type commonMessage interface {
+	ParentHash() phase0.Hash32
+	BlockHash() phase0.Hash32
+	GasLimit() uint64
+	GasUsed() uint64
+}
+
+// bidTraceMessage is the commonMessage of a bid trace.
+type bidTraceMessage struct {
+	trace *apiv1.BidTrace
+}
+
+func (m bidTraceMessage) ParentHash() phase0.Hash32 { return m.trace.ParentHash }
+func (m bidTraceMessage) BlockHash() phase0.Hash32  { return m.trace.BlockHash }
+func (m bidTraceMessage) GasLimit() uint64          { return m.trace.GasLimit }
+func (m bidTraceMessage) GasUsed() uint64           { return m.trace.GasUsed }
+
+// validateCommonFields verifies that the bid describes the block of the submission. The gas limit
+// is also checked with checkGasLimit once it is known to match the bid, before the gas used.
+func validateCommonFields(msg commonMessage, block *types.Block, checkGasLimit func() error) error {
+	if msg.ParentHash() != phase0.Hash32(block.ParentHash()) {
+		return newValidationError(CodeParentHashMismatch, "incorrect ParentHash %s, expected %s", msg.ParentHash().String(), block.ParentHash().String())
+	}
+	if msg.BlockHash() != phase0.Hash32(block.Hash()) {
+		return newValidationError(CodeBlockHashMismatch, "incorrect BlockHash %s, expected %s", msg.BlockHash().String(), block.Hash().String())
+	}
+	if msg.GasLimit() != block.GasLimit() {
+		return newValidationError(CodeGasLimitMismatch, "incorrect GasLimit %d, expected %d", msg.GasLimit(), block.GasLimit())
+	}
+	if err := checkGasLimit(); err != nil {
+		return err
+	}
+	if msg.GasUsed() != block.GasUsed() {
+		return newValidationError(CodeGasUsedMismatch, "incorrect GasUsed %d, expected %d", msg.GasUsed(), block.GasUsed())
+	}
+	return nil
+}
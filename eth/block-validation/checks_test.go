@@ -0,0 +1,497 @@
+package blockvalidation
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/require"
+)
+
+var testSigner = types.LatestSigner(params.AllEthashProtocolChanges)
+
+func signTestTx(t *testing.T, txdata types.TxData) *types.Transaction {
+	t.Helper()
+	tx, err := types.SignNewTx(testKey, testSigner, txdata)
+	require.NoError(t, err)
+	return tx
+}
+
+func TestCheckDuplicateTransactions(t *testing.T) {
+	tx0 := signTestTx(t, &types.LegacyTx{Nonce: 0, To: &common.Address{0x16}, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(params.InitialBaseFee)})
+	tx1 := signTestTx(t, &types.LegacyTx{Nonce: 1, To: &common.Address{0x16}, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(params.InitialBaseFee)})
+
+	require.NoError(t, checkDuplicateTransactions(nil))
+	require.NoError(t, checkDuplicateTransactions(types.Transactions{tx0, tx1}))
+
+	err := checkDuplicateTransactions(types.Transactions{tx0, tx1, tx0})
+	var dupErr ErrDuplicateTransaction
+	require.True(t, errors.As(err, &dupErr))
+	require.Equal(t, tx0.Hash(), dupErr.TxHash)
+}
+
+func TestCheckGasFeeCaps(t *testing.T) {
+	baseFee := big.NewInt(params.InitialBaseFee)
+	legacyOk := signTestTx(t, &types.LegacyTx{Nonce: 0, To: &common.Address{0x16}, Gas: 21000, GasPrice: baseFee})
+	legacyLow := signTestTx(t, &types.LegacyTx{Nonce: 1, To: &common.Address{0x16}, Gas: 21000, GasPrice: big.NewInt(params.InitialBaseFee - 1)})
+	dynamicOk := signTestTx(t, &types.DynamicFeeTx{Nonce: 2, To: &common.Address{0x16}, Gas: 21000, GasFeeCap: baseFee, GasTipCap: common.Big1})
+	dynamicLow := signTestTx(t, &types.DynamicFeeTx{Nonce: 3, To: &common.Address{0x16}, Gas: 21000, GasFeeCap: big.NewInt(params.InitialBaseFee - 1), GasTipCap: common.Big1})
+
+	require.NoError(t, checkGasFeeCaps(types.Transactions{legacyLow}, nil, false))
+	require.NoError(t, checkGasFeeCaps(types.Transactions{legacyOk, dynamicOk}, baseFee, false))
+
+	for _, tx := range []*types.Transaction{legacyLow, dynamicLow} {
+		err := checkGasFeeCaps(types.Transactions{legacyOk, tx}, baseFee, true)
+		var capErr ErrGasFeeCapBelowBaseFee
+		require.True(t, errors.As(err, &capErr))
+		require.Equal(t, tx.Hash(), capErr.TxHash)
+		require.Equal(t, big.NewInt(params.InitialBaseFee-1), capErr.Cap)
+		require.Equal(t, baseFee, capErr.BaseFee)
+	}
+
+	// Without the base fee, only transactions without any fees are accepted.
+	legacyFree := signTestTx(t, &types.LegacyTx{Nonce: 4, To: &common.Address{0x16}, Gas: 21000, GasPrice: common.Big0})
+	dynamicFree := signTestTx(t, &types.DynamicFeeTx{Nonce: 5, To: &common.Address{0x16}, Gas: 21000, GasFeeCap: common.Big0, GasTipCap: common.Big0})
+	require.NoError(t, checkGasFeeCaps(types.Transactions{legacyFree, dynamicFree}, baseFee, true))
+	require.IsType(t, ErrGasFeeCapBelowBaseFee{}, checkGasFeeCaps(types.Transactions{legacyFree}, baseFee, false))
+}
+
+func TestCheckBlockedContracts(t *testing.T) {
+	blocked := common.Address{0xde, 0xad}
+	inBloom := common.Address{0xbe, 0xef}
+	call := signTestTx(t, &types.LegacyTx{Nonce: 0, To: &blocked, Gas: 21000, GasPrice: big.NewInt(params.InitialBaseFee)})
+	other := signTestTx(t, &types.LegacyTx{Nonce: 1, To: &inBloom, Gas: 21000, GasPrice: big.NewInt(params.InitialBaseFee)})
+
+	// Direct calls are found whatever the bloom claims.
+	block := types.NewBlockWithHeader(&types.Header{}).WithBody(types.Transactions{other, call}, nil)
+	require.NoError(t, checkBlockedContracts(block, nil))
+	require.NoError(t, checkBlockedContracts(block, []common.Address{{0x01}}))
+	err := checkBlockedContracts(block, []common.Address{blocked})
+	var blockedErr ErrBlockedContractInteraction
+	require.True(t, errors.As(err, &blockedErr))
+	require.Equal(t, blocked, blockedErr.Address)
+}
+
+func TestCheckBlockedContractLogs(t *testing.T) {
+	blocked := common.Address{0xde, 0xad}
+	inBloom := common.Address{0xbe, 0xef}
+	receipts := types.Receipts{
+		{Logs: []*types.Log{{Address: inBloom}}},
+		{Logs: []*types.Log{{Address: blocked}}},
+	}
+	blockWithBloom := func(logged ...common.Address) *types.Block {
+		var bloom types.Bloom
+		for _, addr := range logged {
+			bloom.Add(addr.Bytes())
+		}
+		return types.NewBlockWithHeader(&types.Header{Bloom: bloom})
+	}
+
+	// Not in the bloom, the receipts are not looked at.
+	require.NoError(t, checkBlockedContractLogs(blockWithBloom(inBloom), receipts, []common.Address{blocked}))
+	// In the bloom but without logs of the blocked contract.
+	require.NoError(t, checkBlockedContractLogs(blockWithBloom(inBloom, blocked), receipts[:1], []common.Address{blocked}))
+	require.NoError(t, checkBlockedContractLogs(blockWithBloom(inBloom, blocked), receipts, nil))
+
+	err := checkBlockedContractLogs(blockWithBloom(inBloom, blocked), receipts, []common.Address{blocked})
+	var blockedErr ErrBlockedContractInteraction
+	require.True(t, errors.As(err, &blockedErr))
+	require.Equal(t, blocked, blockedErr.Address)
+}
+
+func TestCheckTransactionGasLimits(t *testing.T) {
+	fits := signTestTx(t, &types.LegacyTx{Nonce: 0, To: &common.Address{0x16}, Gas: 30_000_000, GasPrice: big.NewInt(params.InitialBaseFee)})
+	tooBig := signTestTx(t, &types.LegacyTx{Nonce: 1, To: &common.Address{0x16}, Gas: 30_000_001, GasPrice: big.NewInt(params.InitialBaseFee)})
+
+	require.NoError(t, checkTransactionGasLimits(types.Transactions{fits}, 30_000_000))
+
+	err := checkTransactionGasLimits(types.Transactions{fits, tooBig}, 30_000_000)
+	var gasErr ErrTransactionGasExceedsBlock
+	require.True(t, errors.As(err, &gasErr))
+	require.Equal(t, ErrTransactionGasExceedsBlock{TxHash: tooBig.Hash(), TxGas: 30_000_001, BlockGas: 30_000_000}, gasErr)
+}
+
+func TestCumulativeGas(t *testing.T) {
+	txWithGas := func(nonce, gas uint64) *types.Transaction {
+		return signTestTx(t, &types.LegacyTx{Nonce: nonce, To: &common.Address{0x16}, Gas: gas, GasPrice: big.NewInt(params.InitialBaseFee)})
+	}
+
+	sum, err := cumulativeGas(nil)
+	require.NoError(t, err)
+	require.Zero(t, sum)
+
+	sum, err = cumulativeGas(types.Transactions{txWithGas(0, 21000), txWithGas(1, 50000)})
+	require.NoError(t, err)
+	require.Equal(t, uint64(71000), sum)
+
+	// Crossing half of the range switches to big integers but still fits.
+	sum, err = cumulativeGas(types.Transactions{txWithGas(0, math.MaxUint64/2), txWithGas(1, 1), txWithGas(2, math.MaxUint64/2)})
+	require.NoError(t, err)
+	require.Equal(t, uint64(math.MaxUint64), sum)
+
+	_, err = cumulativeGas(types.Transactions{txWithGas(0, 1), txWithGas(1, math.MaxUint64)})
+	require.ErrorIs(t, err, ErrCumulativeGasOverflow)
+
+	_, err = cumulativeGas(types.Transactions{txWithGas(0, math.MaxUint64/2+1), txWithGas(1, math.MaxUint64/2+1)})
+	require.ErrorIs(t, err, ErrCumulativeGasOverflow)
+}
+
+func TestCheckInitCodeSizes(t *testing.T) {
+	create := func(nonce uint64, size int) *types.Transaction {
+		return signTestTx(t, &types.LegacyTx{Nonce: nonce, Gas: 10_000_000, GasPrice: big.NewInt(params.InitialBaseFee), Data: make([]byte, size)})
+	}
+	atLimit := create(0, params.MaxInitCodeSize)
+	aboveLimit := create(1, params.MaxInitCodeSize+1)
+	// Only contract creations are subject to the limit.
+	largeCall := signTestTx(t, &types.LegacyTx{Nonce: 2, To: &common.Address{0x16}, Gas: 10_000_000, GasPrice: big.NewInt(params.InitialBaseFee), Data: make([]byte, params.MaxInitCodeSize+1)})
+
+	require.NoError(t, checkInitCodeSizes(types.Transactions{atLimit, largeCall}))
+
+	err := checkInitCodeSizes(types.Transactions{atLimit, aboveLimit})
+	var sizeErr ErrInitCodeTooLarge
+	require.True(t, errors.As(err, &sizeErr))
+	require.Equal(t, ErrInitCodeTooLarge{TxHash: aboveLimit.Hash(), Size: params.MaxInitCodeSize + 1, MaxSize: params.MaxInitCodeSize}, sizeErr)
+}
+
+func TestCheckTipCaps(t *testing.T) {
+	feeCap := big.NewInt(params.InitialBaseFee)
+	equal := signTestTx(t, &types.DynamicFeeTx{Nonce: 0, To: &common.Address{0x16}, Gas: 21000, GasFeeCap: feeCap, GasTipCap: feeCap})
+	above := signTestTx(t, &types.DynamicFeeTx{Nonce: 1, To: &common.Address{0x16}, Gas: 21000, GasFeeCap: feeCap, GasTipCap: big.NewInt(params.InitialBaseFee + 1)})
+	legacy := signTestTx(t, &types.LegacyTx{Nonce: 2, To: &common.Address{0x16}, Gas: 21000, GasPrice: feeCap})
+
+	require.NoError(t, checkTipCaps(types.Transactions{equal, legacy}))
+
+	err := checkTipCaps(types.Transactions{equal, above})
+	var tipErr ErrTipExceedsFeeCap
+	require.True(t, errors.As(err, &tipErr))
+	require.Equal(t, above.Hash(), tipErr.TxHash)
+}
+
+func TestCheckGreedyOrdering(t *testing.T) {
+	baseFee := big.NewInt(params.InitialBaseFee)
+	dynamicTx := func(nonce uint64, feeCap, tip int64) *types.Transaction {
+		return signTestTx(t, &types.DynamicFeeTx{Nonce: nonce, To: &common.Address{0x17}, Gas: 21000, GasFeeCap: big.NewInt(feeCap), GasTipCap: big.NewInt(tip)})
+	}
+	// The effective tip of the second transaction is limited by its fee cap.
+	high := dynamicTx(0, params.InitialBaseFee+3, 3)
+	capped := dynamicTx(1, params.InitialBaseFee+2, 5)
+	low := signTestTx(t, &types.LegacyTx{Nonce: 2, To: &common.Address{0x17}, Gas: 21000, GasPrice: big.NewInt(params.InitialBaseFee + 2)})
+	require.NoError(t, checkGreedyOrdering(types.Transactions{high, capped, low}, baseFee))
+
+	err := checkGreedyOrdering(types.Transactions{capped, high}, baseFee)
+	var orderErr ErrNonGreedyOrdering
+	require.True(t, errors.As(err, &orderErr))
+	require.Equal(t, 1, orderErr.TxIndex)
+	require.Equal(t, int64(2), orderErr.PrevTip.Int64())
+	require.Equal(t, int64(3), orderErr.CurrTip.Int64())
+}
+
+func TestCheckLogOrdering(t *testing.T) {
+	receipts := func(indices ...[]uint) types.Receipts {
+		receipts := make(types.Receipts, len(indices))
+		for i, txIndices := range indices {
+			receipts[i] = &types.Receipt{}
+			for _, index := range txIndices {
+				receipts[i].Logs = append(receipts[i].Logs, &types.Log{TxIndex: uint(i), Index: index})
+			}
+		}
+		return receipts
+	}
+
+	require.NoError(t, checkLogOrdering(nil))
+	require.NoError(t, checkLogOrdering(receipts([]uint{0, 1}, nil, []uint{2})))
+
+	err := checkLogOrdering(receipts([]uint{0, 1}, []uint{3}))
+	require.Equal(t, ErrLogOrderingViolation{TxIndex: 1, LogIndex: 3}, err)
+
+	err = checkLogOrdering(receipts([]uint{1, 0}))
+	require.Equal(t, ErrLogOrderingViolation{TxIndex: 0, LogIndex: 1}, err)
+}
+
+func TestCheckWithdrawalIndices(t *testing.T) {
+	withdrawals := types.Withdrawals{
+		{Index: 0, Validator: 1, Address: common.Address{0x01}, Amount: 10},
+		{Index: 1, Validator: 2, Address: common.Address{0x02}, Amount: 10},
+	}
+	require.NoError(t, checkWithdrawalIndices(withdrawals))
+
+	withdrawals = append(withdrawals, &types.Withdrawal{Index: 1, Validator: 3, Address: common.Address{0x03}, Amount: 10})
+	err := checkWithdrawalIndices(withdrawals)
+	var dupErr ErrDuplicateWithdrawalIndex
+	require.True(t, errors.As(err, &dupErr))
+	require.Equal(t, uint64(1), dupErr.Index)
+}
+
+func TestCheckWithdrawalsOrdering(t *testing.T) {
+	withdrawal := func(index uint64) *types.Withdrawal {
+		return &types.Withdrawal{Index: index, Validator: index, Address: common.Address{0x01}, Amount: 10}
+	}
+	for _, tt := range []struct {
+		name    string
+		indices []uint64
+		err     error
+	}{
+		{"empty", nil, nil},
+		{"ordered", []uint64{3, 4, 5, 9}, nil},
+		{"reversed", []uint64{5, 4, 3}, ErrWithdrawalsOrdering{Previous: 5, Index: 4}},
+		{"partially shuffled", []uint64{3, 4, 6, 5, 7}, ErrWithdrawalsOrdering{Previous: 6, Index: 5}},
+		{"repeated", []uint64{3, 3}, ErrWithdrawalsOrdering{Previous: 3, Index: 3}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var withdrawals types.Withdrawals
+			for _, index := range tt.indices {
+				withdrawals = append(withdrawals, withdrawal(index))
+			}
+			err := checkWithdrawalsOrdering(withdrawals)
+			if tt.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestCheckWithdrawalsRoot(t *testing.T) {
+	withdrawals := types.Withdrawals{
+		{Index: 0, Validator: 1, Address: common.Address{0x01}, Amount: 10},
+		{Index: 1, Validator: 2, Address: common.Address{0x02}, Amount: 10},
+	}
+	for _, tt := range []struct {
+		name        string
+		withdrawals types.Withdrawals
+		root        common.Hash
+		valid       bool
+	}{
+		{"matching root", withdrawals, ComputeWithdrawalsRoot(withdrawals), true},
+		{"mismatched root", withdrawals, ComputeWithdrawalsRoot(withdrawals[:1]), false},
+		{"nil withdrawals with non-zero root", nil, common.Hash{0x01}, false},
+		{"empty withdrawals with empty root", types.Withdrawals{}, types.EmptyRootHash, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkWithdrawalsRoot(tt.withdrawals, tt.root)
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Equal(t, CodeWithdrawalsRootMismatch, validationErr.Code)
+		})
+	}
+}
+
+func TestCheckTimestamp(t *testing.T) {
+	const (
+		genesisTime = 1_000_000
+		parentSlot  = 100
+		parentTime  = genesisTime + parentSlot*12
+	)
+	slotStart := func(slots int) time.Time { return time.Unix(parentTime+int64(slots)*12, 0) }
+	for _, tt := range []struct {
+		name        string
+		timestamp   uint64
+		slot        uint64
+		genesisTime uint64
+		now         time.Time
+		valid       bool
+	}{
+		{"next slot", parentTime + 12, parentSlot + 1, genesisTime, slotStart(0).Add(4 * time.Second), true},
+		{"late for the slot", parentTime + 12, parentSlot + 1, genesisTime, slotStart(1).Add(time.Second), true},
+		{"too late for the slot", parentTime + 12, parentSlot + 1, genesisTime, slotStart(1).Add(2 * time.Second), false},
+		{"after a missed slot", parentTime + 24, parentSlot + 2, genesisTime, slotStart(1).Add(11 * time.Second), true},
+		{"off the slot start", parentTime + 13, parentSlot + 1, genesisTime, slotStart(0), false},
+		{"other slot than the bid", parentTime + 24, parentSlot + 1, genesisTime, slotStart(1), false},
+		{"far future", parentTime + 1200, parentSlot + 100, genesisTime, slotStart(0), false},
+		{"before parent", parentTime - 12, parentSlot - 1, genesisTime, slotStart(0), false},
+		{"at parent", parentTime, parentSlot, genesisTime, slotStart(0), false},
+		{"unknown genesis", parentTime + 24, parentSlot + 1, 0, slotStart(1), true},
+		{"unknown genesis off the slot start", parentTime + 13, parentSlot + 1, 0, slotStart(0), false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTimestamp(parentTime, tt.timestamp, tt.slot, tt.genesisTime, tt.now, 12*time.Second, time.Second)
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Equal(t, CodeTimestampMismatch, validationErr.Code)
+		})
+	}
+
+	// Sub-second slots, which skipped the config validation, do not divide by zero.
+	require.NoError(t, checkTimestamp(parentTime, parentTime+1, parentSlot+1, 0, slotStart(0), 500*time.Millisecond, time.Second))
+}
+
+func TestCheckRegisteredGasLimit(t *testing.T) {
+	const registered = 30_000_000
+	const tolerance = registered / 1024
+	converged := core.CalcGasLimit(20_000_000, registered)
+	for _, tt := range []struct {
+		name             string
+		gasLimit, parent uint64
+		valid            bool
+	}{
+		{"registered", registered, registered, true},
+		{"above registered", registered + 1, registered, false},
+		{"below registered", registered - 1, registered, false},
+		{"upper bound", registered + tolerance, registered, false},
+		{"lower bound", registered - tolerance, registered, false},
+		{"converging from the parent", converged, 20_000_000, true},
+		{"converging too slowly", converged - 1, 20_000_000, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRegisteredGasLimit(tt.gasLimit, registered, tt.parent)
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			require.Equal(t, CodeGasLimitOutOfRange, validationErr.Code)
+		})
+	}
+}
+
+func TestCheckPayloadRoundTrip(t *testing.T) {
+	header := &types.Header{
+		ParentHash: common.Hash{0x01},
+		UncleHash:  types.EmptyUncleHash,
+		Coinbase:   common.Address{0x02},
+		Difficulty: common.Big0,
+		Number:     big.NewInt(10),
+		GasLimit:   30_000_000,
+		Time:       1000,
+		Extra:      []byte("builder"),
+		BaseFee:    big.NewInt(params.InitialBaseFee),
+	}
+	txs := types.Transactions{signTestTx(t, &types.DynamicFeeTx{To: &common.Address{0x18}, Gas: 21000, GasFeeCap: big.NewInt(params.InitialBaseFee), GasTipCap: common.Big1})}
+	withdrawals := types.Withdrawals{{Index: 3, Validator: 4, Address: common.Address{0x05}, Amount: 6}}
+	block := types.NewBlockWithWithdrawals(header, txs, nil, nil, withdrawals, trie.NewStackTrie(nil))
+
+	payload, err := engine.BlockToExecutionPayloadV2(block)
+	require.NoError(t, err)
+	decoded, err := engine.ExecutionPayloadV2ToBlock(payload)
+	require.NoError(t, err)
+	require.NoError(t, checkPayloadRoundTrip(decoded, payload))
+
+	payload.ExtraData = []byte("tampered")
+	require.ErrorIs(t, checkPayloadRoundTrip(decoded, payload), ErrPayloadRoundTripMismatch)
+}
+
+func TestCheckPayloadVersion(t *testing.T) {
+	shanghaiTime := uint64(100)
+	config := &params.ChainConfig{ShanghaiTime: &shanghaiTime}
+	preShanghai := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Time: shanghaiTime - 1})
+	postShanghai := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(2), Time: shanghaiTime})
+
+	require.NoError(t, checkPayloadVersion(config, preShanghai, spec.DataVersionBellatrix))
+	require.NoError(t, checkPayloadVersion(config, postShanghai, spec.DataVersionCapella))
+
+	require.Equal(t, ErrWrongPayloadVersion{ExpectedVersion: "capella", GotVersion: "bellatrix"}, checkPayloadVersion(config, postShanghai, spec.DataVersionBellatrix))
+	require.Equal(t, ErrWrongPayloadVersion{ExpectedVersion: "bellatrix", GotVersion: "capella"}, checkPayloadVersion(config, preShanghai, spec.DataVersionCapella))
+}
+
+func TestCheckPrivateTxRatio(t *testing.T) {
+	public := signTestTx(t, &types.LegacyTx{Nonce: 0, To: &common.Address{0x17}, Gas: 21000, GasPrice: big.NewInt(params.InitialBaseFee)})
+	private := signTestTx(t, &types.LegacyTx{Nonce: 1, To: &common.Address{0x17}, Gas: 21000, GasPrice: big.NewInt(params.InitialBaseFee)})
+	inPool := func(hash common.Hash) bool { return hash == public.Hash() }
+
+	require.Equal(t, 0.0, privateTxRatio(nil, inPool))
+	require.Equal(t, 0.5, privateTxRatio(types.Transactions{public, private}, inPool))
+	require.Equal(t, 1.0, privateTxRatio(types.Transactions{private}, inPool))
+
+	require.NoError(t, checkPrivateTxRatio(0.5, 0))
+	require.NoError(t, checkPrivateTxRatio(0.5, 0.5))
+	require.ErrorIs(t, checkPrivateTxRatio(0.5, 0.6), ErrTooManyPublicTransactions)
+}
+
+func TestCheckExtraData(t *testing.T) {
+	prefixes := [][]byte{[]byte("relay"), []byte("ok:")}
+
+	require.NoError(t, checkExtraData([]byte("anything"), nil))
+	require.NoError(t, checkExtraData(nil, [][]byte{}))
+	require.NoError(t, checkExtraData([]byte("ok:"), prefixes))
+	require.NoError(t, checkExtraData([]byte("relay-compliant builder"), prefixes))
+	require.ErrorIs(t, checkExtraData([]byte("builder"), prefixes), ErrExtraDataViolation)
+	require.ErrorIs(t, checkExtraData([]byte("rel"), prefixes), ErrExtraDataViolation)
+	require.ErrorIs(t, checkExtraData(nil, prefixes), ErrExtraDataViolation)
+}
+
+func TestCheckTransactionCount(t *testing.T) {
+	require.NoError(t, checkTransactionCount(50_000, 0))
+	require.NoError(t, checkTransactionCount(99, 100))
+	require.NoError(t, checkTransactionCount(100, 100))
+	require.ErrorIs(t, checkTransactionCount(101, 100), ErrTooManyTransactions)
+}
+
+func TestCheckBuilderCoinbase(t *testing.T) {
+	builder := phase0.BLSPubKey{0x01}
+	registry := map[[48]byte]common.Address{builder: {0xaa}}
+
+	require.NoError(t, checkBuilderCoinbase(nil, builder, common.Address{0xbb}))
+	require.NoError(t, checkBuilderCoinbase(registry, builder, common.Address{0xaa}))
+	require.NoError(t, checkBuilderCoinbase(registry, phase0.BLSPubKey{0x02}, common.Address{0xbb}))
+	require.Equal(t, ErrCoinbaseMismatch{Got: common.Address{0xbb}, Expected: common.Address{0xaa}}, checkBuilderCoinbase(registry, builder, common.Address{0xbb}))
+}
+
+func TestCheckZeroAddressTransactions(t *testing.T) {
+	creation := signTestTx(t, &types.LegacyTx{Nonce: 0, Gas: 100000, GasPrice: common.Big1})
+	transfer := signTestTx(t, &types.LegacyTx{Nonce: 1, To: &common.Address{0x16}, Gas: 21000, GasPrice: common.Big1})
+	zero := signTestTx(t, &types.DynamicFeeTx{Nonce: 2, To: &common.Address{}, Gas: 21000, GasFeeCap: common.Big1, GasTipCap: common.Big1})
+
+	// A contract creation has no recipient, it is not a zero address send.
+	require.Nil(t, creation.To())
+	require.NoError(t, checkZeroAddressTransactions(types.Transactions{creation, transfer}))
+
+	require.Equal(t, ErrZeroAddressTransaction{TxIndex: 2, TxHash: zero.Hash()}, checkZeroAddressTransactions(types.Transactions{creation, transfer, zero}))
+	require.Equal(t, ErrZeroAddressTransaction{TxIndex: 0, TxHash: zero.Hash()}, checkZeroAddressTransactions(types.Transactions{zero, creation}))
+}
+
+func TestValidateCommonFields(t *testing.T) {
+	block := types.NewBlockWithHeader(&types.Header{ParentHash: common.Hash{0x01}, Number: common.Big1, GasLimit: 30_000_000, GasUsed: 21000})
+	valid := func() *apiv1.BidTrace {
+		return &apiv1.BidTrace{ParentHash: phase0.Hash32{0x01}, BlockHash: phase0.Hash32(block.Hash()), GasLimit: block.GasLimit(), GasUsed: block.GasUsed()}
+	}
+	noCheck := func() error { return nil }
+	require.NoError(t, validateCommonFields(bidTraceMessage{valid()}, block, noCheck))
+
+	for _, tt := range []struct {
+		modify func(*apiv1.BidTrace)
+		code   ValidationErrorCode
+	}{
+		{func(m *apiv1.BidTrace) { m.ParentHash = phase0.Hash32{0x02} }, CodeParentHashMismatch},
+		{func(m *apiv1.BidTrace) { m.BlockHash = phase0.Hash32{0x02} }, CodeBlockHashMismatch},
+		{func(m *apiv1.BidTrace) { m.GasLimit++ }, CodeGasLimitMismatch},
+		{func(m *apiv1.BidTrace) { m.GasUsed++ }, CodeGasUsedMismatch},
+	} {
+		msg := valid()
+		tt.modify(msg)
+		var validationErr *ValidationError
+		require.ErrorAs(t, validateCommonFields(bidTraceMessage{msg}, block, noCheck), &validationErr)
+		require.Equal(t, tt.code, validationErr.Code)
+	}
+
+	// The gas limit is checked after the gas limit of the bid and before the gas used.
+	outOfRange := newValidationError(CodeGasLimitOutOfRange, "out of range")
+	checkGasLimit := func() error { return outOfRange }
+	msg := valid()
+	msg.GasUsed++
+	require.Equal(t, outOfRange, validateCommonFields(bidTraceMessage{msg}, block, checkGasLimit))
+	msg.GasLimit++
+	var validationErr *ValidationError
+	require.ErrorAs(t, validateCommonFields(bidTraceMessage{msg}, block, checkGasLimit), &validationErr)
+	require.Equal(t, CodeGasLimitMismatch, validationErr.Code)
+}
@@ -0,0 +1,85 @@
+package blockvalidation
+
+import (
+	"sync"
+	"time"
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreaker stops calls to a failing dependency. It opens after a number of consecutive
+// failures and lets a single trial call through once the recovery timeout has passed. The
+// circuit closes again if the trial succeeds and stays open otherwise.
+type CircuitBreaker struct {
+	threshold       int
+	recoveryTimeout time.Duration
+	now             func() time.Time
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker opening after threshold consecutive failures.
+func NewCircuitBreaker(threshold int, recoveryTimeout time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{
+		threshold:       threshold,
+		recoveryTimeout: recoveryTimeout,
+		now:             time.Now,
+	}
+}
+
+// Allow reports whether a call may be made. An open circuit turns half-open once the recovery
+// timeout has passed, allowing one trial call.
+func (cb *CircuitBreaker) Allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		if cb.now().Sub(cb.openedAt) < cb.recoveryTimeout {
+			return false
+		}
+		cb.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		// The trial call is in flight.
+		return false
+	default:
+		return true
+	}
+}
+
+// Success records a successful call, closing the circuit.
+func (cb *CircuitBreaker) Success() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.state = circuitClosed
+	cb.failures = 0
+}
+
+// Failure records a failed call, opening the circuit if the trial call or too many calls in a row failed.
+func (cb *CircuitBreaker) Failure() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == circuitOpen {
+		return
+	}
+	cb.failures++
+	if cb.state == circuitHalfOpen || cb.failures >= cb.threshold {
+		cb.state = circuitOpen
+		cb.openedAt = cb.now()
+	}
+}
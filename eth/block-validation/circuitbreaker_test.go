@@ -0,0 +1,39 @@
+package blockvalidation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	cb := NewCircuitBreaker(2, time.Minute)
+	now := time.Now()
+	cb.now = func() time.Time { return now }
+
+	// A success resets the failure count.
+	cb.Failure()
+	cb.Success()
+	cb.Failure()
+	require.True(t, cb.Allow())
+
+	cb.Failure()
+	require.False(t, cb.Allow())
+
+	// After the recovery timeout a single trial call is allowed.
+	now = now.Add(time.Minute)
+	require.True(t, cb.Allow())
+	require.False(t, cb.Allow())
+
+	// A failed trial opens the circuit again.
+	cb.Failure()
+	require.False(t, cb.Allow())
+	now = now.Add(time.Minute)
+	require.True(t, cb.Allow())
+
+	// A successful trial closes it.
+	cb.Success()
+	require.True(t, cb.Allow())
+	require.True(t, cb.Allow())
+}
@@ -0,0 +1,66 @@
+package blockvalidation
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// ValidationDiff describes how two validation outcomes of the same submission diverge.
+type ValidationDiff struct {
+	AgreesOnValidity bool
+	// ProfitDelta is b's profit minus a's, nil unless both outcomes have a replay result.
+	ProfitDelta *big.Int
+	// GasUsedDelta is b's gas used minus a's, zero unless both outcomes have a block.
+	GasUsedDelta int64
+	// MismatchedFields names every field the outcomes disagree on.
+	MismatchedFields []string
+}
+
+// CompareValidationResults compares the outcomes of validating the same submission on two
+// backends, e.g. to cross-check execution clients.
+func CompareValidationResults(a, b ValidationOutcome) ValidationDiff {
+	diff := ValidationDiff{AgreesOnValidity: (a.Err == nil) == (b.Err == nil)}
+	mismatch := func(field string) {
+		diff.MismatchedFields = append(diff.MismatchedFields, field)
+	}
+	if !diff.AgreesOnValidity {
+		mismatch("validity")
+	}
+
+	switch {
+	case (a.Block == nil) != (b.Block == nil):
+		mismatch("block")
+	case a.Block != nil:
+		if a.Block.Hash() != b.Block.Hash() {
+			mismatch("blockHash")
+		}
+		diff.GasUsedDelta = int64(b.Block.GasUsed()) - int64(a.Block.GasUsed())
+		if diff.GasUsedDelta != 0 {
+			mismatch("gasUsed")
+		}
+	}
+
+	switch {
+	case (a.Result == nil) != (b.Result == nil):
+		mismatch("result")
+	case a.Result != nil:
+		diff.ProfitDelta = new(big.Int).Sub(b.Result.Profit, a.Result.Profit)
+		if diff.ProfitDelta.Sign() != 0 {
+			mismatch("profit")
+		}
+		if a.Result.FeeRecipientBalanceDelta.Cmp(b.Result.FeeRecipientBalanceDelta) != 0 {
+			mismatch("feeRecipientBalanceDelta")
+		}
+		if receiptsRoot(a.Result.Receipts) != receiptsRoot(b.Result.Receipts) {
+			mismatch("receipts")
+		}
+	}
+	return diff
+}
+
+func receiptsRoot(receipts types.Receipts) common.Hash {
+	return types.DeriveSha(receipts, trie.NewStackTrie(nil))
+}
@@ -0,0 +1,97 @@
+package blockvalidation
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareValidationResults(t *testing.T) {
+	block := func(gasUsed uint64, extra byte) *types.Block {
+		return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), GasUsed: gasUsed, Extra: []byte{extra}})
+	}
+	result := func(profit, delta int64, receipts ...*types.Receipt) *core.PayloadValidationResult {
+		return &core.PayloadValidationResult{Receipts: receipts, Profit: big.NewInt(profit), FeeRecipientBalanceDelta: big.NewInt(delta)}
+	}
+	receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000}
+	failedReceipt := &types.Receipt{Status: types.ReceiptStatusFailed, CumulativeGasUsed: 21000}
+	valid := ValidationOutcome{Block: block(21000, 0), Result: result(10, 10, receipt)}
+	invalidErr := errors.New("invalid")
+
+	tests := []struct {
+		name string
+		a, b ValidationOutcome
+		want ValidationDiff
+	}{
+		{
+			name: "identical",
+			a:    valid,
+			b:    ValidationOutcome{Block: block(21000, 0), Result: result(10, 10, receipt)},
+			want: ValidationDiff{AgreesOnValidity: true, ProfitDelta: big.NewInt(0)},
+		},
+		{
+			name: "both invalid",
+			a:    ValidationOutcome{Block: block(21000, 0), Err: invalidErr},
+			b:    ValidationOutcome{Block: block(21000, 0), Err: errors.New("other reason")},
+			want: ValidationDiff{AgreesOnValidity: true},
+		},
+		{
+			name: "validity",
+			a:    valid,
+			b:    ValidationOutcome{Block: block(21000, 0), Err: invalidErr},
+			want: ValidationDiff{MismatchedFields: []string{"validity", "result"}},
+		},
+		{
+			name: "missing block",
+			a:    ValidationOutcome{Err: invalidErr},
+			b:    ValidationOutcome{Block: block(21000, 0), Err: invalidErr},
+			want: ValidationDiff{AgreesOnValidity: true, MismatchedFields: []string{"block"}},
+		},
+		{
+			name: "block hash",
+			a:    valid,
+			b:    ValidationOutcome{Block: block(21000, 1), Result: result(10, 10, receipt)},
+			want: ValidationDiff{AgreesOnValidity: true, ProfitDelta: big.NewInt(0), MismatchedFields: []string{"blockHash"}},
+		},
+		{
+			name: "gas used",
+			a:    valid,
+			b:    ValidationOutcome{Block: block(20000, 0), Result: result(10, 10, receipt)},
+			want: ValidationDiff{AgreesOnValidity: true, ProfitDelta: big.NewInt(0), GasUsedDelta: -1000, MismatchedFields: []string{"blockHash", "gasUsed"}},
+		},
+		{
+			name: "profit",
+			a:    valid,
+			b:    ValidationOutcome{Block: block(21000, 0), Result: result(15, 10, receipt)},
+			want: ValidationDiff{AgreesOnValidity: true, ProfitDelta: big.NewInt(5), MismatchedFields: []string{"profit"}},
+		},
+		{
+			name: "fee recipient balance delta",
+			a:    valid,
+			b:    ValidationOutcome{Block: block(21000, 0), Result: result(10, 3, receipt)},
+			want: ValidationDiff{AgreesOnValidity: true, ProfitDelta: big.NewInt(0), MismatchedFields: []string{"feeRecipientBalanceDelta"}},
+		},
+		{
+			name: "receipts",
+			a:    valid,
+			b:    ValidationOutcome{Block: block(21000, 0), Result: result(10, 10, failedReceipt)},
+			want: ValidationDiff{AgreesOnValidity: true, ProfitDelta: big.NewInt(0), MismatchedFields: []string{"receipts"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := CompareValidationResults(tt.a, tt.b)
+			if tt.want.ProfitDelta == nil {
+				require.Nil(t, diff.ProfitDelta)
+			} else {
+				require.Zero(t, tt.want.ProfitDelta.Cmp(diff.ProfitDelta), "profit delta %s", diff.ProfitDelta)
+			}
+			tt.want.ProfitDelta, diff.ProfitDelta = nil, nil
+			require.Equal(t, tt.want, diff)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package blockvalidation
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/naoina/toml"
+)
+
+// tomlSettings make TOML keys use the same names as the fields of BlockValidationConfig, like
+// the geth configuration file.
+var tomlSettings = toml.Config{
+	NormFieldName: func(rt reflect.Type, key string) string {
+		return key
+	},
+	FieldToKey: func(rt reflect.Type, field string) string {
+		return field
+	},
+	MissingField: func(rt reflect.Type, field string) error {
+		return fmt.Errorf("field '%s' is not defined in %s", field, rt.String())
+	},
+}
+
+const configHeader = `# Block validation configuration. Durations are in nanoseconds and zero values disable the
+# corresponding check. The beacon client, withdrawals oracle, MEV classifier, audit signing key,
+# relay pubkey, genesis fork version, builder coinbase registry, transaction filters, metrics
+# registerer and hooks can only be set in code.
+
+`
+
+// DefaultBlockValidationConfig returns the configuration with the defaults applied to unset fields.
+func DefaultBlockValidationConfig() BlockValidationConfig {
+	return BlockValidationConfig{
+		BeaconClientTimeout:    defaultBeaconClientTimeout,
+		BeaconFailureThreshold: defaultBeaconFailureThreshold,
+		MinProfit:              new(big.Int),
+		Events: ValidationEventsConfig{
+			MaxSubscribers: defaultMaxEventSubscribers,
+			BufferSize:     defaultEventBufferSize,
+		},
+		SlotDuration:    defaultSlotDuration,
+		MaxPayloadBytes: defaultMaxPayloadBytes,
+	}
+}
+
+// LoadConfig reads a BlockValidationConfig from a TOML file. Fields missing from the file are
+// left unset.
+func LoadConfig(path string) (BlockValidationConfig, error) {
+	var cfg BlockValidationConfig
+	f, err := os.Open(path)
+	if err != nil {
+		return cfg, err
+	}
+	defer f.Close()
+
+	if err := tomlSettings.NewDecoder(bufio.NewReader(f)).Decode(&cfg); err != nil {
+		// Add the file name to errors that have a line number.
+		if _, ok := err.(*toml.LineError); ok {
+			err = errors.New(path + ", " + err.Error())
+		}
+		return BlockValidationConfig{}, err
+	}
+	if err := cfg.validate(); err != nil {
+		return BlockValidationConfig{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// WriteDefaultConfig writes a TOML template of all configurable fields with their defaults.
+func WriteDefaultConfig(w io.Writer) error {
+	out, err := tomlSettings.Marshal(DefaultBlockValidationConfig())
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, configHeader); err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// validate checks the fields that can hold invalid values.
+func (cfg *BlockValidationConfig) validate() error {
+	if !cfg.ProfitMode.valid() {
+		return fmt.Errorf("unknown profit mode %q", cfg.ProfitMode)
+	}
+	if cfg.ValidationTimeout < 0 {
+		return fmt.Errorf("negative validation timeout %v", cfg.ValidationTimeout)
+	}
+	if cfg.SlotDuration < 0 || cfg.SlotDuration%time.Second != 0 {
+		return fmt.Errorf("slot duration %v is not a whole number of seconds", cfg.SlotDuration)
+	}
+	if cfg.AnomalyThresholdSigma < 0 {
+		return fmt.Errorf("negative anomaly threshold %v", cfg.AnomalyThresholdSigma)
+	}
+	if cfg.CircuitBreaker.MaxHeadAgeSeconds < 0 {
+		return fmt.Errorf("negative maximum head age %d", cfg.CircuitBreaker.MaxHeadAgeSeconds)
+	}
+	return nil
+}
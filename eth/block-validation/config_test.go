@@ -0,0 +1,87 @@
+package blockvalidation
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	dir, err := os.MkdirTemp(os.TempDir(), "blockvalidation")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	path := writeConfigFile(t, `
+UseBalanceDiffProfit = true
+ProfitMode = "feesOnly"
+ValidationTimeout = 2000000000
+AllowedFeeRecipients = ["0x0100000000000000000000000000000000000000"]
+MinProfit = "1000"
+
+[MinProfitByRecipient]
+0x0200000000000000000000000000000000000000 = "5"
+
+[Events]
+MaxSubscribers = 4
+`)
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	require.True(t, cfg.UseBalanceDiffProfit)
+	require.Equal(t, ProfitModeFeesOnly, cfg.ProfitMode)
+	require.Equal(t, 2*time.Second, cfg.ValidationTimeout)
+	require.Equal(t, []common.Address{{0x01}}, cfg.AllowedFeeRecipients)
+	require.Equal(t, big.NewInt(1000), cfg.MinProfit)
+	require.Equal(t, map[common.Address]*big.Int{{0x02}: big.NewInt(5)}, cfg.MinProfitByRecipient)
+	require.Equal(t, 4, cfg.Events.MaxSubscribers)
+	// Fields missing from the file are left unset.
+	require.Zero(t, cfg.SlotDuration)
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"unknown field":    `NoSuchField = true`,
+		"wrong type":       `UseBalanceDiffProfit = "yes"`,
+		"profit mode":      `ProfitMode = "everything"`,
+		"negative timeout": `ValidationTimeout = -1`,
+		"partial seconds":  `SlotDuration = 1500000000`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadConfig(writeConfigFile(t, content))
+			require.Error(t, err)
+		})
+	}
+
+	_, err := LoadConfig(filepath.Join(os.TempDir(), "blockvalidation-missing.toml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestWriteDefaultConfig(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteDefaultConfig(&buf))
+	require.Contains(t, buf.String(), "SlotDuration = 12000000000")
+	require.Contains(t, buf.String(), "[Events]")
+	require.NotContains(t, buf.String(), "BeaconClient =")
+
+	cfg, err := LoadConfig(writeConfigFile(t, buf.String()))
+	require.NoError(t, err)
+	defaults := DefaultBlockValidationConfig()
+	require.Equal(t, defaults.SlotDuration, cfg.SlotDuration)
+	require.Equal(t, defaults.MaxPayloadBytes, cfg.MaxPayloadBytes)
+	require.Equal(t, defaults.BeaconFailureThreshold, cfg.BeaconFailureThreshold)
+	require.Equal(t, defaults.Events, cfg.Events)
+	require.Zero(t, cfg.MinProfit.Sign())
+	require.Empty(t, cfg.AllowedFeeRecipients)
+}
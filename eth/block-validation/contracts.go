@@ -0,0 +1,126 @@
+package blockvalidation
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// contractAccessTracer counts the unique contracts called during the replay of a block and
+// aborts the execution once more than max of them were called.
+type contractAccessTracer struct {
+	max       int
+	env       *vm.EVM
+	contracts map[common.Address]struct{}
+}
+
+func newContractAccessTracer(max int) *contractAccessTracer {
+	return &contractAccessTracer{max: max, contracts: make(map[common.Address]struct{})}
+}
+
+// exceeded reports whether more than the allowed number of contracts were called.
+func (t *contractAccessTracer) exceeded() bool {
+	return len(t.contracts) > t.max
+}
+
+func (t *contractAccessTracer) access(to common.Address) {
+	if t.env == nil || t.env.StateDB.GetCodeSize(to) == 0 {
+		return
+	}
+	t.contracts[to] = struct{}{}
+	if t.exceeded() {
+		t.env.Cancel()
+	}
+}
+
+func (t *contractAccessTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *contractAccessTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *contractAccessTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	if !create {
+		t.access(to)
+	}
+}
+
+func (t *contractAccessTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *contractAccessTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if typ != vm.CREATE && typ != vm.CREATE2 {
+		t.access(to)
+	}
+}
+
+func (t *contractAccessTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (t *contractAccessTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *contractAccessTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
+
+// multiTracer forwards the EVM events to all of its tracers.
+type multiTracer []vm.EVMLogger
+
+// addTracer adds the tracer to the ones already configured.
+func addTracer(config *vm.Config, tracer vm.EVMLogger) {
+	switch existing := config.Tracer.(type) {
+	case nil:
+		config.Tracer = tracer
+	case multiTracer:
+		config.Tracer = append(existing, tracer)
+	default:
+		config.Tracer = multiTracer{existing, tracer}
+	}
+	config.Debug = true
+}
+
+func (m multiTracer) CaptureTxStart(gasLimit uint64) {
+	for _, t := range m {
+		t.CaptureTxStart(gasLimit)
+	}
+}
+
+func (m multiTracer) CaptureTxEnd(restGas uint64) {
+	for _, t := range m {
+		t.CaptureTxEnd(restGas)
+	}
+}
+
+func (m multiTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	for _, t := range m {
+		t.CaptureStart(env, from, to, create, input, gas, value)
+	}
+}
+
+func (m multiTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	for _, t := range m {
+		t.CaptureEnd(output, gasUsed, err)
+	}
+}
+
+func (m multiTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	for _, t := range m {
+		t.CaptureEnter(typ, from, to, input, gas, value)
+	}
+}
+
+func (m multiTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	for _, t := range m {
+		t.CaptureExit(output, gasUsed, err)
+	}
+}
+
+func (m multiTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	for _, t := range m {
+		t.CaptureState(pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func (m multiTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	for _, t := range m {
+		t.CaptureFault(pc, op, gas, cost, scope, depth, err)
+	}
+}
@@ -0,0 +1,82 @@
+package blockvalidation
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// defaultDedupWindowSlots is used when BlockValidationConfig.DedupWindowSlots is not set.
+const defaultDedupWindowSlots = 2
+
+// slotDedup keeps the outcomes of the V2 validations of the latest slots by slot and block hash,
+// so that a builder retrying the same submission within a slot gets the earlier outcome. Unlike
+// the validation cache, the same block submitted for a later slot is validated again. The window
+// advances with the highest slot submitted for, which is bounded by the slot of the chain head.
+// Outcomes are kept under the same conditions as in the validation cache, so that retries of
+// submissions failing on an unknown parent are validated again.
+type slotDedup struct {
+	window uint64
+
+	mu      sync.Mutex
+	highest uint64
+	slots   map[uint64]map[common.Hash]*validationCacheEntry
+}
+
+func newSlotDedup(window uint64) *slotDedup {
+	if window == 0 {
+		window = defaultDedupWindowSlots
+	}
+	return &slotDedup{window: window, slots: make(map[uint64]map[common.Hash]*validationCacheEntry)}
+}
+
+// inWindow reports whether the slot is one of the latest window slots.
+func (d *slotDedup) inWindow(slot uint64) bool {
+	return slot <= d.highest && d.highest-slot < d.window
+}
+
+// get returns the outcome of an earlier validation of the request for the slot.
+func (d *slotDedup) get(slot uint64, blockHash, digest common.Hash) (*validationCacheEntry, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.inWindow(slot) {
+		return nil, false
+	}
+	entry, ok := d.slots[slot][blockHash]
+	if !ok || entry.digest != digest {
+		return nil, false
+	}
+	return entry, true
+}
+
+// add records the outcome of a validation for the slot, advancing the window to the slot. Slots
+// after maxSlot are not recorded, so that submissions for far future slots can not move the
+// window past the current ones.
+func (d *slotDedup) add(slot, maxSlot uint64, blockHash common.Hash, entry *validationCacheEntry) {
+	if slot > maxSlot {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if slot > d.highest {
+		d.highest = slot
+		for s := range d.slots {
+			if !d.inWindow(s) {
+				delete(d.slots, s)
+			}
+		}
+	}
+	if !d.inWindow(slot) {
+		return
+	}
+	hashes, ok := d.slots[slot]
+	if !ok {
+		hashes = make(map[common.Hash]*validationCacheEntry)
+		d.slots[slot] = hashes
+	}
+	if _, ok := hashes[blockHash]; !ok {
+		hashes[blockHash] = entry
+	}
+}
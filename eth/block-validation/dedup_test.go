@@ -0,0 +1,93 @@
+package blockvalidation
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlotDedup(t *testing.T) {
+	dedup := newSlotDedup(0)
+	hash, digest := common.Hash{0x01}, common.Hash{0x02}
+	invalid := errors.New("invalid")
+
+	_, ok := dedup.get(10, hash, digest)
+	require.False(t, ok)
+
+	dedup.add(10, math.MaxUint64, hash, &validationCacheEntry{digest: digest, err: invalid})
+	entry, ok := dedup.get(10, hash, digest)
+	require.True(t, ok)
+	require.Equal(t, invalid, entry.err)
+	// The first outcome is kept.
+	dedup.add(10, math.MaxUint64, hash, &validationCacheEntry{digest: digest})
+	entry, _ = dedup.get(10, hash, digest)
+	require.Equal(t, invalid, entry.err)
+
+	// A different request with the same block hash, or the same block in another slot.
+	_, ok = dedup.get(10, hash, common.Hash{0x03})
+	require.False(t, ok)
+	_, ok = dedup.get(11, hash, digest)
+	require.False(t, ok)
+
+	// The window covers the two latest slots.
+	dedup.add(11, math.MaxUint64, hash, &validationCacheEntry{digest: digest})
+	_, ok = dedup.get(10, hash, digest)
+	require.True(t, ok)
+	dedup.add(12, math.MaxUint64, common.Hash{0x04}, &validationCacheEntry{digest: digest})
+	_, ok = dedup.get(10, hash, digest)
+	require.False(t, ok)
+	require.NotContains(t, dedup.slots, uint64(10))
+
+	// Outcomes of slots behind the window are not recorded.
+	dedup.add(9, math.MaxUint64, hash, &validationCacheEntry{digest: digest})
+	require.NotContains(t, dedup.slots, uint64(9))
+
+	// Slots after the slot of the chain head do not move the window.
+	dedup.add(math.MaxUint64, 13, hash, &validationCacheEntry{digest: digest})
+	require.Equal(t, uint64(12), dedup.highest)
+	_, ok = dedup.get(12, common.Hash{0x04}, digest)
+	require.True(t, ok)
+	_, ok = dedup.get(math.MaxUint64, hash, digest)
+	require.False(t, ok)
+}
+
+func TestValidateBuilderSubmissionV2_DedupSkippedBeaconCheck(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	client := &testBeaconClient{randao: common.Hash(req.ExecutionPayload.PrevRandao), proposer: req.Message.ProposerPubkey, err: errors.New("connection refused")}
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{
+		UseBalanceDiffProfit: true,
+		AllowIndirectPayment: true,
+		BeaconClient:         client,
+	})
+
+	// The outcome of a submission the beacon checks were skipped for is not reused.
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.Equal(t, 3, client.calls)
+	client.err = nil
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.Equal(t, 6, client.calls)
+
+	// Once the checks ran, the duplicate is answered with the earlier outcome.
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.Equal(t, 6, client.calls)
+}
@@ -0,0 +1,82 @@
+package blockvalidation
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// depositEventTopic is the signature hash of the deposit contract event
+// DepositEvent(bytes pubkey, bytes withdrawal_credentials, bytes amount, bytes signature, bytes index).
+var depositEventTopic = common.HexToHash("0x649bbc62d0e31342afea4e5cd82d4049e7e1ee912fc0889aa790803be39038c5")
+
+// depositEventFields are the byte lengths of the DepositEvent fields in declaration order.
+var depositEventFields = []struct {
+	name string
+	size int
+}{
+	{"pubkey", 48},
+	{"withdrawal_credentials", 32},
+	{"amount", 8},
+	{"signature", 96},
+	{"index", 8},
+}
+
+// depositEventDataSize is the size of the ABI encoded DepositEvent data: the five offsets
+// followed by each field as a length word and its contents padded to full words.
+const depositEventDataSize = 576
+
+// VerifyDepositLog checks that a log emitted by the deposit contract is a DepositEvent
+// with the canonical ABI layout and field sizes.
+func VerifyDepositLog(log *types.Log) error {
+	malformed := func(format string, args ...interface{}) error {
+		return ErrMalformedDepositLog{TxHash: log.TxHash, Reason: fmt.Sprintf(format, args...)}
+	}
+	if len(log.Topics) != 1 || log.Topics[0] != depositEventTopic {
+		return malformed("unexpected topics")
+	}
+	if len(log.Data) != depositEventDataSize {
+		return malformed("data size %d, expected %d", len(log.Data), depositEventDataSize)
+	}
+
+	readWord := func(offset int) (uint64, bool) {
+		word := log.Data[offset : offset+32]
+		for _, b := range word[:24] {
+			if b != 0 {
+				return 0, false
+			}
+		}
+		return binary.BigEndian.Uint64(word[24:]), true
+	}
+
+	expectedOffset := 32 * len(depositEventFields)
+	for i, field := range depositEventFields {
+		offset, ok := readWord(32 * i)
+		if !ok || offset != uint64(expectedOffset) {
+			return malformed("%s at offset %d, expected %d", field.name, offset, expectedOffset)
+		}
+		size, ok := readWord(expectedOffset)
+		if !ok || size != uint64(field.size) {
+			return malformed("%s size %d, expected %d", field.name, size, field.size)
+		}
+		expectedOffset += 32 + (field.size+31)/32*32
+	}
+	return nil
+}
+
+// checkDepositLogs verifies all logs emitted by the deposit contract in the block receipts.
+func checkDepositLogs(receipts types.Receipts, depositContract common.Address) error {
+	for _, receipt := range receipts {
+		for _, l := range receipt.Logs {
+			if l.Address != depositContract {
+				continue
+			}
+			if err := VerifyDepositLog(l); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package blockvalidation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func packDepositEvent(t *testing.T, sizes ...int) []byte {
+	t.Helper()
+	bytesTy, err := abi.NewType("bytes", "", nil)
+	require.NoError(t, err)
+	var (
+		args   abi.Arguments
+		values []interface{}
+	)
+	for _, size := range sizes {
+		args = append(args, abi.Argument{Type: bytesTy})
+		values = append(values, make([]byte, size))
+	}
+	data, err := args.Pack(values...)
+	require.NoError(t, err)
+	return data
+}
+
+func TestVerifyDepositLog(t *testing.T) {
+	txHash := common.Hash{0x01}
+	depositLog := func(topics []common.Hash, data []byte) *types.Log {
+		return &types.Log{Topics: topics, Data: data, TxHash: txHash}
+	}
+	valid := packDepositEvent(t, 48, 32, 8, 96, 8)
+	require.NoError(t, VerifyDepositLog(depositLog([]common.Hash{depositEventTopic}, valid)))
+
+	for name, l := range map[string]*types.Log{
+		"no topics":       depositLog(nil, valid),
+		"wrong topic":     depositLog([]common.Hash{{0x02}}, valid),
+		"extra topic":     depositLog([]common.Hash{depositEventTopic, {0x02}}, valid),
+		"truncated":       depositLog([]common.Hash{depositEventTopic}, valid[:len(valid)-32]),
+		"wrong sizes":     depositLog([]common.Hash{depositEventTopic}, packDepositEvent(t, 32, 48, 8, 96, 8)),
+		"wrong arguments": depositLog([]common.Hash{depositEventTopic}, packDepositEvent(t, 48, 32, 8, 96, 8, 0)[:depositEventDataSize]),
+	} {
+		err := VerifyDepositLog(l)
+		var depositErr ErrMalformedDepositLog
+		require.True(t, errors.As(err, &depositErr), name)
+		require.Equal(t, txHash, depositErr.TxHash, name)
+	}
+}
+
+func TestCheckDepositLogs(t *testing.T) {
+	depositContract := common.Address{0x42}
+	malformed := &types.Log{Address: depositContract, TxHash: common.Hash{0x01}}
+	receipts := types.Receipts{{Logs: []*types.Log{{Address: common.Address{0x43}}}}}
+	require.NoError(t, checkDepositLogs(receipts, depositContract))
+
+	receipts = append(receipts, &types.Receipt{Logs: []*types.Log{malformed}})
+	var depositErr ErrMalformedDepositLog
+	require.True(t, errors.As(checkDepositLogs(receipts, depositContract), &depositErr))
+	require.Equal(t, malformed.TxHash, depositErr.TxHash)
+}
@@ -0,0 +1,78 @@
+package blockvalidation
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// diskIOTracker sums the bytes read from storage during the replays of the latest slot. The
+// reads are taken from the I/O counters of the process, so reads by other parts of the node that
+// happen during a replay, including concurrent replays, are attributed to it as well. This is
+// only precise enough to be reported as a metric.
+type diskIOTracker struct {
+	readBytes func() (int64, error)
+
+	mu    sync.Mutex
+	slot  uint64
+	bytes int64
+}
+
+func newDiskIOTracker() *diskIOTracker {
+	return &diskIOTracker{readBytes: procReadBytes}
+}
+
+// measure starts measuring the reads of a replay in the slot. The returned function stops the
+// measurement and adds the reads to the slot.
+func (t *diskIOTracker) measure(slot uint64) func() {
+	start, err := t.readBytes()
+	if err != nil {
+		return func() {}
+	}
+	return func() {
+		end, err := t.readBytes()
+		if err != nil {
+			return
+		}
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		switch {
+		case slot > t.slot:
+			t.slot, t.bytes = slot, end-start
+		case slot == t.slot:
+			t.bytes += end - start
+		default:
+			return
+		}
+		replayDiskReadBytesGauge.Update(t.bytes)
+	}
+}
+
+// procReadBytes returns the bytes the process caused to be read from storage.
+func procReadBytes() (int64, error) {
+	file, err := os.Open("/proc/self/io")
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	return parseReadBytes(file)
+}
+
+// parseReadBytes extracts the read_bytes counter from the contents of /proc/<pid>/io.
+func parseReadBytes(r io.Reader) (int64, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "read_bytes:") {
+			return strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "read_bytes:")), 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, errors.New("no read_bytes counter")
+}
@@ -0,0 +1,41 @@
+package blockvalidation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReadBytes(t *testing.T) {
+	io := "rchar: 323934931\nwchar: 323929600\nsyscr: 632687\nsyscw: 632675\nread_bytes: 4096\nwrite_bytes: 323932160\ncancelled_write_bytes: 0\n"
+	read, err := parseReadBytes(strings.NewReader(io))
+	require.NoError(t, err)
+	require.EqualValues(t, 4096, read)
+
+	_, err = parseReadBytes(strings.NewReader("rchar: 323934931\n"))
+	require.Error(t, err)
+}
+
+func TestDiskIOTracker(t *testing.T) {
+	var read int64
+	tracker := newDiskIOTracker()
+	tracker.readBytes = func() (int64, error) { return read, nil }
+	replay := func(slot uint64, bytes int64) {
+		stop := tracker.measure(slot)
+		read += bytes
+		stop()
+	}
+
+	replay(1, 60)
+	replay(1, 60)
+	require.EqualValues(t, 120, tracker.bytes)
+
+	// The reads are counted per slot.
+	replay(2, 10)
+	require.EqualValues(t, 10, tracker.bytes)
+
+	// Late replays of earlier slots are not counted.
+	replay(1, 200)
+	require.EqualValues(t, 2, tracker.slot)
+}
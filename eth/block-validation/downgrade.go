@@ -0,0 +1,44 @@
+package blockvalidation
+
+import (
+	"errors"
+
+	bellatrixapi "github.com/attestantio/go-builder-client/api/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+)
+
+// DowngradeToV1 converts a V2 request into a V1 request, for relays falling back to nodes
+// without the V2 endpoint. Only payloads without withdrawals can be converted. The block hash
+// is copied unchanged, as it is not possible to re-sign the submission.
+func DowngradeToV1(r *BuilderBlockValidationRequestV2) (*BuilderBlockValidationRequest, error) {
+	if r.ExecutionPayload == nil {
+		return nil, errors.New("nil execution payload")
+	}
+	if len(r.ExecutionPayload.Withdrawals) > 0 {
+		return nil, ErrDowngradeNotPossible
+	}
+	payload := r.ExecutionPayload
+	return &BuilderBlockValidationRequest{
+		SubmitBlockRequest: bellatrixapi.SubmitBlockRequest{
+			Message: r.Message,
+			ExecutionPayload: &bellatrix.ExecutionPayload{
+				ParentHash:    payload.ParentHash,
+				FeeRecipient:  payload.FeeRecipient,
+				StateRoot:     payload.StateRoot,
+				ReceiptsRoot:  payload.ReceiptsRoot,
+				LogsBloom:     payload.LogsBloom,
+				PrevRandao:    payload.PrevRandao,
+				BlockNumber:   payload.BlockNumber,
+				GasLimit:      payload.GasLimit,
+				GasUsed:       payload.GasUsed,
+				Timestamp:     payload.Timestamp,
+				ExtraData:     payload.ExtraData,
+				BaseFeePerGas: payload.BaseFeePerGas,
+				BlockHash:     payload.BlockHash,
+				Transactions:  payload.Transactions,
+			},
+			Signature: r.Signature,
+		},
+		RegisteredGasLimit: r.RegisteredGasLimit,
+	}, nil
+}
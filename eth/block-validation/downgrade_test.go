@@ -0,0 +1,59 @@
+package blockvalidation
+
+import (
+	"testing"
+
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDowngradeToV1(t *testing.T) {
+	req := &BuilderBlockValidationRequestV2{
+		SubmitBlockRequest: capellaapi.SubmitBlockRequest{
+			Message: &apiv1.BidTrace{Slot: 7},
+			ExecutionPayload: &capella.ExecutionPayload{
+				ParentHash:    phase0.Hash32{0x01},
+				FeeRecipient:  bellatrix.ExecutionAddress{0x02},
+				StateRoot:     [32]byte{0x03},
+				BlockNumber:   10,
+				GasLimit:      30_000_000,
+				GasUsed:       21000,
+				Timestamp:     1234,
+				ExtraData:     []byte{0x04},
+				BaseFeePerGas: [32]byte{0x05},
+				BlockHash:     phase0.Hash32{0x06},
+				Transactions:  []bellatrix.Transaction{{0x07}},
+			},
+			Signature: phase0.BLSSignature{0x08},
+		},
+		RegisteredGasLimit: 30_000_000,
+		ExtraEIPs:          []int{3855},
+	}
+	downgraded, err := DowngradeToV1(req)
+	require.NoError(t, err)
+	require.Equal(t, req.Message, downgraded.Message)
+	require.Equal(t, req.Signature, downgraded.Signature)
+	require.Equal(t, req.RegisteredGasLimit, downgraded.RegisteredGasLimit)
+	payload := downgraded.ExecutionPayload
+	require.Equal(t, req.ExecutionPayload.ParentHash, payload.ParentHash)
+	require.Equal(t, req.ExecutionPayload.FeeRecipient, payload.FeeRecipient)
+	require.Equal(t, req.ExecutionPayload.StateRoot, payload.StateRoot)
+	require.Equal(t, req.ExecutionPayload.BlockNumber, payload.BlockNumber)
+	require.Equal(t, req.ExecutionPayload.GasUsed, payload.GasUsed)
+	require.Equal(t, req.ExecutionPayload.ExtraData, payload.ExtraData)
+	require.Equal(t, req.ExecutionPayload.BaseFeePerGas, payload.BaseFeePerGas)
+	require.Equal(t, req.ExecutionPayload.BlockHash, payload.BlockHash)
+	require.Equal(t, req.ExecutionPayload.Transactions, payload.Transactions)
+
+	req.ExecutionPayload.Withdrawals = []*capella.Withdrawal{{Index: 1}}
+	_, err = DowngradeToV1(req)
+	require.ErrorIs(t, err, ErrDowngradeNotPossible)
+
+	req.ExecutionPayload = nil
+	_, err = DowngradeToV1(req)
+	require.Error(t, err)
+}
@@ -0,0 +1,296 @@
+package blockvalidation
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ValidationErrorCode identifies the check a submission failed. The values are stable, they are
+// used as metric labels and in the audit log.
+type ValidationErrorCode string
+
+const (
+	CodeNilRequest              ValidationErrorCode = "ErrNilRequest"
+	CodeNilMessage              ValidationErrorCode = "ErrNilMessage"
+	CodeNilPayload              ValidationErrorCode = "ErrNilPayload"
+	CodeParentHashMismatch      ValidationErrorCode = "ErrParentHashMismatch"
+	CodeBlockHashMismatch       ValidationErrorCode = "ErrBlockHashMismatch"
+	CodeGasLimitMismatch        ValidationErrorCode = "ErrGasLimitMismatch"
+	CodeGasUsedMismatch         ValidationErrorCode = "ErrGasUsedMismatch"
+	CodeInsufficientProfit      ValidationErrorCode = "ErrInsufficientProfit"
+	CodeWithdrawalsRootMismatch ValidationErrorCode = "ErrWithdrawalsRootMismatch"
+	CodeInvalidSignature        ValidationErrorCode = "ErrInvalidSignature"
+	CodeBaseFeeMismatch         ValidationErrorCode = "ErrBaseFeeMismatch"
+	CodeTimestampMismatch       ValidationErrorCode = "ErrTimestampMismatch"
+	CodeGasLimitOutOfRange      ValidationErrorCode = "ErrGasLimitOutOfRange"
+	CodeBlacklistedAddress      ValidationErrorCode = "ErrBlacklistedAddress"
+	CodeUnsupportedEIP          ValidationErrorCode = "ErrUnsupportedEIP"
+	CodePrevRandaoMismatch      ValidationErrorCode = "ErrPrevRandaoMismatch"
+	CodeProposerPubkeyMismatch  ValidationErrorCode = "ErrProposerPubkeyMismatch"
+	CodeNoRelayPubkey           ValidationErrorCode = "ErrNoRelayPubkey"
+)
+
+// ValidationError is returned when a submission fails the check identified by Code. Callers
+// can use errors.As to tell the checks apart instead of matching the error message.
+type ValidationError struct {
+	Code   ValidationErrorCode
+	Detail string
+	err    error
+}
+
+func newValidationError(code ValidationErrorCode, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Code: code, Detail: fmt.Sprintf(format, args...)}
+}
+
+// wrapValidationError attributes err to the check identified by code, keeping it in the chain
+// of wrapped errors.
+func wrapValidationError(code ValidationErrorCode, err error) *ValidationError {
+	return &ValidationError{Code: code, Detail: err.Error(), err: err}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Detail
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.err
+}
+
+var (
+	ErrTooManyWithdrawals             = errors.New("too many withdrawals")
+	ErrMergeNotActivated              = errors.New("proof-of-stake block submitted before the merge was reached")
+	ErrNilTransactions                = errors.New("nil execution payload transactions")
+	ErrCustomEIPsNotAllowed           = errors.New("custom EIPs not allowed")
+	ErrCumulativeGasOverflow          = errors.New("cumulative transaction gas overflows uint64")
+	ErrWithdrawalAmountMismatch       = errors.New("withdrawal amount mismatch")
+	ErrGenesisHashMismatch            = errors.New("genesis hash mismatch")
+	ErrPayloadRoundTripMismatch       = errors.New("re-encoded payload differs from the submitted payload")
+	ErrTooManyContractAccesses        = errors.New("too many unique contracts accessed")
+	ErrProfitMispriced                = errors.New("block value deviates from the price oracle")
+	ErrExcessiveTrieDepth             = errors.New("excessive state trie depth")
+	ErrInvalidWithdrawalListSignature = errors.New("invalid withdrawal list signature")
+	ErrInvalidBuilderSignature        = errors.New("invalid builder signature")
+	ErrUnknownFeeRecipient            = errors.New("unknown fee recipient")
+	ErrPayloadTooLarge                = errors.New("request too large")
+	ErrTooManyPublicTransactions      = errors.New("too many public transactions")
+	ErrProfitModeViolation            = errors.New("proposer payment violates the profit mode")
+	ErrDowngradeNotPossible           = errors.New("payloads with withdrawals can not be downgraded to V1")
+	ErrSSZDisabled                    = errors.New("SSZ submissions are disabled")
+	ErrBuilderStatsResetDisabled      = errors.New("resetting builder stats is disabled")
+	ErrExtraDataViolation             = errors.New("extra data violates the required prefixes")
+	ErrTooManyTransactions            = errors.New("too many transactions")
+	ErrUnknownParent                  = errors.New("unknown parent")
+	ErrNonCanonicalParent             = errors.New("parent is not canonical")
+	ErrCircuitOpen                    = errors.New("head of the chain is stale, not accepting submissions")
+	ErrBeaconValidationFailed         = errors.New("beacon node validation failed")
+	ErrVMConfigOverridesNotAllowed    = errors.New("VM config overrides not allowed")
+	ErrInvalidVMConfigOverrides       = errors.New("invalid VM config overrides")
+	ErrAnomalousProfit                = errors.New("anomalous profit")
+	ErrNoEthService                   = errors.New("not available without an eth service")
+)
+
+// ErrDuplicateTransaction is returned when the same transaction is included in a block more than once.
+type ErrDuplicateTransaction struct {
+	TxHash common.Hash
+}
+
+func (e ErrDuplicateTransaction) Error() string {
+	return fmt.Sprintf("duplicate transaction %s", e.TxHash.String())
+}
+
+// ErrGasFeeCapBelowBaseFee is returned when a transaction can not pay the base fee of the block.
+type ErrGasFeeCapBelowBaseFee struct {
+	TxHash  common.Hash
+	Cap     *big.Int
+	BaseFee *big.Int
+}
+
+func (e ErrGasFeeCapBelowBaseFee) Error() string {
+	return fmt.Sprintf("transaction %s gas fee cap %s below base fee %s", e.TxHash.String(), e.Cap, e.BaseFee)
+}
+
+// ErrLogOrderingViolation is returned when the logs in the block receipts are not in emission order.
+type ErrLogOrderingViolation struct {
+	TxIndex  int
+	LogIndex uint
+}
+
+func (e ErrLogOrderingViolation) Error() string {
+	return fmt.Sprintf("log ordering violation in transaction %d at log index %d", e.TxIndex, e.LogIndex)
+}
+
+// ErrWrongPayloadVersion is returned when the execution payload version does not match the fork active for the block.
+type ErrWrongPayloadVersion struct {
+	ExpectedVersion string
+	GotVersion      string
+}
+
+func (e ErrWrongPayloadVersion) Error() string {
+	return fmt.Sprintf("wrong execution payload version %s, expected %s", e.GotVersion, e.ExpectedVersion)
+}
+
+// ErrBlockedContractInteraction is returned when a transaction in the block calls a blocked contract.
+type ErrBlockedContractInteraction struct {
+	Address common.Address
+}
+
+func (e ErrBlockedContractInteraction) Error() string {
+	return fmt.Sprintf("transaction calls blocked contract %s", e.Address.String())
+}
+
+// ErrMalformedDepositLog is returned when a deposit contract log does not have the DepositEvent format.
+type ErrMalformedDepositLog struct {
+	TxHash common.Hash
+	Reason string
+}
+
+func (e ErrMalformedDepositLog) Error() string {
+	return fmt.Sprintf("malformed deposit log in transaction %s: %s", e.TxHash.String(), e.Reason)
+}
+
+// ErrTransactionGasExceedsBlock is returned when a transaction gas limit is above the block gas limit.
+type ErrTransactionGasExceedsBlock struct {
+	TxHash   common.Hash
+	TxGas    uint64
+	BlockGas uint64
+}
+
+func (e ErrTransactionGasExceedsBlock) Error() string {
+	return fmt.Sprintf("transaction %s gas limit %d exceeds block gas limit %d", e.TxHash.String(), e.TxGas, e.BlockGas)
+}
+
+// ErrInitCodeTooLarge is returned when a contract creation exceeds the EIP-3860 initcode size limit.
+type ErrInitCodeTooLarge struct {
+	TxHash  common.Hash
+	Size    int
+	MaxSize int
+}
+
+func (e ErrInitCodeTooLarge) Error() string {
+	return fmt.Sprintf("transaction %s initcode size %d exceeds limit %d", e.TxHash.String(), e.Size, e.MaxSize)
+}
+
+// ErrCoinbaseMismatch is returned when the block coinbase is not the proposer fee recipient
+// and indirect payments are not allowed, or not the coinbase registered for the builder.
+type ErrCoinbaseMismatch struct {
+	Got      common.Address
+	Expected common.Address
+}
+
+func (e ErrCoinbaseMismatch) Error() string {
+	return fmt.Sprintf("coinbase %s is not the expected %s", e.Got.String(), e.Expected.String())
+}
+
+// ErrBlockedMEVType is returned when a transaction extracts MEV of a type the relay does not accept.
+type ErrBlockedMEVType struct {
+	EventType string
+	TxHash    common.Hash
+}
+
+func (e ErrBlockedMEVType) Error() string {
+	return fmt.Sprintf("transaction %s extracts blocked MEV type %s", e.TxHash.String(), e.EventType)
+}
+
+// ErrDifficultyMismatch is returned when the block difficulty is not the one required by the consensus rules.
+type ErrDifficultyMismatch struct {
+	Expected *big.Int
+	Got      *big.Int
+}
+
+func (e ErrDifficultyMismatch) Error() string {
+	return fmt.Sprintf("incorrect difficulty %s, expected %s", e.Got, e.Expected)
+}
+
+// ErrTipExceedsFeeCap is returned when a dynamic fee transaction has a priority fee above its fee cap.
+type ErrTipExceedsFeeCap struct {
+	TxHash common.Hash
+}
+
+func (e ErrTipExceedsFeeCap) Error() string {
+	return fmt.Sprintf("transaction %s tip cap exceeds fee cap", e.TxHash.String())
+}
+
+// ErrDuplicateWithdrawalIndex is returned when two withdrawals in a block share the same index.
+type ErrDuplicateWithdrawalIndex struct {
+	Index uint64
+}
+
+func (e ErrDuplicateWithdrawalIndex) Error() string {
+	return fmt.Sprintf("duplicate withdrawal index %d", e.Index)
+}
+
+// ErrWithdrawalsOrdering is returned when the withdrawal indices in a block do not strictly increase.
+type ErrWithdrawalsOrdering struct {
+	Previous uint64
+	Index    uint64
+}
+
+func (e ErrWithdrawalsOrdering) Error() string {
+	return fmt.Sprintf("withdrawal index %d follows index %d", e.Index, e.Previous)
+}
+
+// ErrBuilderBlocked is returned for submissions of a builder blocked after too many consecutive failures.
+type ErrBuilderBlocked struct {
+	Builder phase0.BLSPubKey
+}
+
+func (e ErrBuilderBlocked) Error() string {
+	return fmt.Sprintf("builder %s blocked after consecutive validation failures", e.Builder.String())
+}
+
+// ErrNonGreedyOrdering is returned when a transaction pays a higher effective tip than the one before it.
+type ErrNonGreedyOrdering struct {
+	TxIndex int
+	PrevTip *big.Int
+	CurrTip *big.Int
+}
+
+func (e ErrNonGreedyOrdering) Error() string {
+	return fmt.Sprintf("transaction %d tip %s exceeds previous transaction tip %s", e.TxIndex, e.CurrTip, e.PrevTip)
+}
+
+// ErrGasPadding is returned when a transaction declares more than 10% above its estimated gas.
+type ErrGasPadding struct {
+	TxHash   common.Hash
+	Gas      uint64
+	Estimate uint64
+}
+
+func (e ErrGasPadding) Error() string {
+	return fmt.Sprintf("transaction %s gas limit %d exceeds estimate %d by more than 10%%", e.TxHash.String(), e.Gas, e.Estimate)
+}
+
+// ErrNonceRegression is returned when a sender reuses a nonce of an earlier slot in the blocks of a builder.
+type ErrNonceRegression struct {
+	Sender   common.Address
+	Expected uint64
+	Got      uint64
+}
+
+func (e ErrNonceRegression) Error() string {
+	return fmt.Sprintf("nonce regression for sender %s: expected at least %d, got %d", e.Sender.String(), e.Expected, e.Got)
+}
+
+// ErrBlockedRecipient is returned by BlockedRecipientFilter for a transaction sent to a blocked address.
+type ErrBlockedRecipient struct {
+	TxHash  common.Hash
+	Address common.Address
+}
+
+func (e ErrBlockedRecipient) Error() string {
+	return fmt.Sprintf("transaction %s sent to blocked address %s", e.TxHash.String(), e.Address.String())
+}
+
+// ErrZeroAddressTransaction is returned when a transaction of the block is sent to the zero address.
+type ErrZeroAddressTransaction struct {
+	TxIndex int
+	TxHash  common.Hash
+}
+
+func (e ErrZeroAddressTransaction) Error() string {
+	return fmt.Sprintf("transaction %d (%s) is sent to the zero address", e.TxIndex, e.TxHash.String())
+}
@@ -0,0 +1,137 @@
+package blockvalidation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	defaultMaxEventSubscribers = 16
+	defaultEventBufferSize     = 64
+)
+
+var errTooManySubscribers = errors.New("too many validation event subscribers")
+
+// ValidationEventsConfig limits the subscriptions to validation events.
+type ValidationEventsConfig struct {
+	// Maximum number of concurrent subscribers, 16 if zero.
+	MaxSubscribers int
+	// Number of events buffered per subscriber before further events are dropped for it, 64 if zero.
+	BufferSize int
+}
+
+// ValidationEvent is streamed to subscribers for every V2 validation.
+type ValidationEvent struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Slot          uint64    `json:"slot"`
+	BuilderPubkey string    `json:"builderPubkey"`
+	BlockHash     string    `json:"blockHash"`
+	Valid         bool      `json:"valid"`
+	Error         string    `json:"error,omitempty"`
+	DurationMs    int64     `json:"durationMs"`
+}
+
+func newValidationEvent(msg *apiv1.BidTrace, duration time.Duration, err error) ValidationEvent {
+	event := ValidationEvent{
+		Timestamp:  time.Now().UTC(),
+		Valid:      err == nil,
+		DurationMs: duration.Milliseconds(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if msg != nil {
+		event.Slot = msg.Slot
+		event.BuilderPubkey = msg.BuilderPubkey.String()
+		event.BlockHash = msg.BlockHash.String()
+	}
+	return event
+}
+
+// eventHub broadcasts validation events to subscribers. Unlike event.Feed, sending never
+// blocks: events are dropped for subscribers whose buffer is full.
+type eventHub struct {
+	maxSubscribers int
+	bufferSize     int
+
+	mu   sync.Mutex
+	subs map[chan ValidationEvent]struct{}
+}
+
+func newEventHub(cfg ValidationEventsConfig) *eventHub {
+	hub := &eventHub{
+		maxSubscribers: cfg.MaxSubscribers,
+		bufferSize:     cfg.BufferSize,
+		subs:           make(map[chan ValidationEvent]struct{}),
+	}
+	if hub.maxSubscribers <= 0 {
+		hub.maxSubscribers = defaultMaxEventSubscribers
+	}
+	if hub.bufferSize <= 0 {
+		hub.bufferSize = defaultEventBufferSize
+	}
+	return hub
+}
+
+// subscribe returns a channel receiving the published events and a function to end the subscription.
+func (h *eventHub) subscribe() (<-chan ValidationEvent, func(), error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.subs) >= h.maxSubscribers {
+		return nil, nil, errTooManySubscribers
+	}
+	ch := make(chan ValidationEvent, h.bufferSize)
+	h.subs[ch] = struct{}{}
+	unsubscribe := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs, ch)
+	}
+	return ch, unsubscribe, nil
+}
+
+func (h *eventHub) publish(event ValidationEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- event:
+		default:
+			validationEventsDroppedCounter.Inc(1)
+		}
+	}
+}
+
+// ValidationEvents streams a ValidationEvent for every V2 validation, subscribed to with
+// flashbots_subscribe("validationEvents"). Events are dropped for subscribers that fall behind.
+func (api *BlockValidationAPI) ValidationEvents(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	events, unsubscribe, err := api.events.subscribe()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case event := <-events:
+				notifier.Notify(rpcSub.ID, event)
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
@@ -0,0 +1,76 @@
+package blockvalidation
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventHub(t *testing.T) {
+	hub := newEventHub(ValidationEventsConfig{MaxSubscribers: 2, BufferSize: 1})
+
+	slow, unsubscribeSlow, err := hub.subscribe()
+	require.NoError(t, err)
+	fast, unsubscribeFast, err := hub.subscribe()
+	require.NoError(t, err)
+	_, _, err = hub.subscribe()
+	require.ErrorIs(t, err, errTooManySubscribers)
+
+	// Publishing does not block on the full buffer of the slow subscriber.
+	hub.publish(ValidationEvent{Slot: 1})
+	require.Equal(t, uint64(1), (<-fast).Slot)
+	hub.publish(ValidationEvent{Slot: 2})
+	require.Equal(t, uint64(2), (<-fast).Slot)
+	require.Equal(t, uint64(1), (<-slow).Slot)
+	require.Empty(t, slow)
+
+	// Unsubscribing frees up a slot.
+	unsubscribeSlow()
+	_, _, err = hub.subscribe()
+	require.NoError(t, err)
+	unsubscribeFast()
+}
+
+func TestValidationEventsSubscription(t *testing.T) {
+	api, err := NewBlockValidationAPI(nil, nil, true, nil)
+	require.NoError(t, err)
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("flashbots", api))
+	defer server.Stop()
+	httpServer := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer httpServer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	client, err := rpc.DialWebsocket(ctx, "ws"+strings.TrimPrefix(httpServer.URL, "http"), "")
+	require.NoError(t, err)
+	defer client.Close()
+
+	events := make(chan ValidationEvent)
+	sub, err := client.Subscribe(ctx, "flashbots", events, "validationEvents")
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+
+	// A submission without a payload is rejected before touching the chain.
+	req := &BuilderBlockValidationRequestV2{SubmitBlockRequest: capellaapi.SubmitBlockRequest{Message: &apiv1.BidTrace{Slot: 7}}}
+	require.Error(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	select {
+	case event := <-events:
+		require.Equal(t, uint64(7), event.Slot)
+		require.False(t, event.Valid)
+		require.Equal(t, "nil execution payload", event.Error)
+		require.Equal(t, req.Message.BuilderPubkey.String(), event.BuilderPubkey)
+	case err := <-sub.Err():
+		t.Fatal(err)
+	case <-ctx.Done():
+		t.Fatal("no validation event received")
+	}
+}
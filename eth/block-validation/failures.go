@@ -0,0 +1,58 @@
+package blockvalidation
+
+import (
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// failureTracker counts the consecutive failed validations of every builder. Builders whose
+// streak reaches maxConsecutive are reported, and with autoBlockAfter set, builders whose
+// streak reaches it are rejected until the node restarts.
+type failureTracker struct {
+	maxConsecutive int
+	autoBlockAfter int
+
+	mu      sync.Mutex
+	streaks map[phase0.BLSPubKey]int
+	blocked map[phase0.BLSPubKey]struct{}
+}
+
+func newFailureTracker(maxConsecutive, autoBlockAfter int) *failureTracker {
+	return &failureTracker{
+		maxConsecutive: maxConsecutive,
+		autoBlockAfter: autoBlockAfter,
+		streaks:        make(map[phase0.BLSPubKey]int),
+		blocked:        make(map[phase0.BLSPubKey]struct{}),
+	}
+}
+
+// isBlocked reports whether the builder was blocked after too many consecutive failures.
+func (t *failureTracker) isBlocked(builder phase0.BLSPubKey) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.blocked[builder]
+	return ok
+}
+
+// record updates the streak of the builder with the outcome of a validation.
+func (t *failureTracker) record(builder phase0.BLSPubKey, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err == nil {
+		delete(t.streaks, builder)
+		return
+	}
+	t.streaks[builder]++
+	streak := t.streaks[builder]
+	if t.maxConsecutive > 0 && streak == t.maxConsecutive {
+		builderConsecutiveFailuresCounter.Inc(1)
+		log.Error("builder reached consecutive validation failure limit", "builder", builder.String(), "failures", streak, "err", err)
+	}
+	if t.autoBlockAfter > 0 && streak == t.autoBlockAfter {
+		t.blocked[builder] = struct{}{}
+		log.Error("blocking builder after consecutive validation failures", "builder", builder.String(), "failures", streak)
+	}
+}
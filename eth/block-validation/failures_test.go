@@ -0,0 +1,36 @@
+package blockvalidation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailureTracker(t *testing.T) {
+	tracker := newFailureTracker(2, 3)
+	builder := phase0.BLSPubKey{0x01}
+	other := phase0.BLSPubKey{0x02}
+	errInvalid := errors.New("invalid block")
+
+	tracker.record(builder, errInvalid)
+	require.Equal(t, 1, tracker.streaks[builder])
+
+	// A success resets the streak.
+	tracker.record(builder, nil)
+	require.NotContains(t, tracker.streaks, builder)
+
+	for i := 0; i < 2; i++ {
+		tracker.record(builder, errInvalid)
+	}
+	require.False(t, tracker.isBlocked(builder))
+
+	tracker.record(builder, errInvalid)
+	require.True(t, tracker.isBlocked(builder))
+
+	// Streaks are kept per builder.
+	tracker.record(other, errInvalid)
+	require.Equal(t, 1, tracker.streaks[other])
+	require.False(t, tracker.isBlocked(other))
+}
@@ -0,0 +1,89 @@
+package blockvalidation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/log"
+
+	// Register the native tracers, the call tracer reports revert reasons.
+	_ "github.com/ethereum/go-ethereum/eth/tracers/native"
+)
+
+// failureTraceReexec is the number of blocks the parent state of a failed block is regenerated
+// from if it is not available.
+const failureTraceReexec = 128
+
+// failedTransaction is the first transaction of a block that could not be applied or reverted.
+type failedTransaction struct {
+	index int
+	tx    *types.Transaction
+	err   error
+	trace string
+}
+
+// traceFailure re-executes the transactions of a block that failed validation one by one with the
+// call tracer and logs the first one failing. Blocks failing after all their transactions were
+// applied, for example on the proposer payment, are logged as such.
+func (api *BlockValidationAPI) traceFailure(ctx context.Context, block *types.Block, validationErr error) {
+	failed, err := api.findFailedTransaction(ctx, block, true)
+	switch {
+	case err != nil:
+		log.Warn("could not trace failed block", "hash", block.Hash(), "err", err)
+	case failed == nil:
+		log.Info("all transactions of failed block were applied", "hash", block.Hash(), "validationErr", validationErr)
+	default:
+		log.Error("transaction of failed block failed", "hash", block.Hash(), "index", failed.index, "tx", failed.tx.Hash(), "err", failed.err, "trace", failed.trace, "validationErr", validationErr)
+	}
+}
+
+// findFailedTransaction returns the first transaction of the block that could not be applied, or
+// that reverted if includeReverted is set.
+func (api *BlockValidationAPI) findFailedTransaction(ctx context.Context, block *types.Block, includeReverted bool) (*failedTransaction, error) {
+	if api.eth == nil {
+		return nil, ErrNoEthService
+	}
+	parent := api.chain.GetBlockByHash(block.ParentHash())
+	if parent == nil {
+		return nil, fmt.Errorf("unknown parent %s", block.ParentHash())
+	}
+	statedb, release, err := api.eth.APIBackend.StateAtBlock(ctx, parent, failureTraceReexec, nil, true, false)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	config := api.chain.Config()
+	signer := types.MakeSigner(config, block.Number())
+	blockContext := core.NewEVMBlockContext(block.Header(), api.chain, nil)
+	gp := new(core.GasPool).AddGas(block.GasLimit())
+	for i, tx := range block.Transactions() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		msg, err := core.TransactionToMessage(tx, signer, block.BaseFee())
+		if err != nil {
+			return &failedTransaction{index: i, tx: tx, err: err}, nil
+		}
+		tracer, err := tracers.DefaultDirectory.New("callTracer", &tracers.Context{BlockHash: block.Hash(), BlockNumber: block.Number(), TxIndex: i, TxHash: tx.Hash()}, nil)
+		if err != nil {
+			return nil, err
+		}
+		statedb.SetTxContext(tx.Hash(), i)
+		evm := vm.NewEVM(blockContext, core.NewEVMTxContext(msg), statedb, config, vm.Config{Tracer: tracer, Debug: true})
+		result, err := core.ApplyMessage(evm, msg, gp)
+		if err != nil {
+			return &failedTransaction{index: i, tx: tx, err: err}, nil
+		}
+		if result.Failed() && includeReverted {
+			trace, _ := tracer.GetResult()
+			return &failedTransaction{index: i, tx: tx, err: result.Err, trace: string(trace)}, nil
+		}
+		statedb.Finalise(true)
+	}
+	return nil, nil
+}
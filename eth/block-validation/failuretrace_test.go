@@ -0,0 +1,70 @@
+package blockvalidation
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceOnFailure(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	nonce := statedb.GetNonce(testAddr)
+	signer := types.LatestSigner(bc.Config())
+	gasPrice := big.NewInt(2 * baseFee.Int64())
+	transfer, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x16}, big.NewInt(10), 21000, gasPrice, nil), signer, testKey)
+	// PUSH1 0 PUSH1 0 REVERT
+	revert, _ := types.SignTx(types.NewContractCreation(nonce+1, common.Big0, 100_000, gasPrice, hexutil.MustDecode("0x60006000fd")), signer, testKey)
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, TraceOnFailure: true})
+
+	toBlock := func(req *BuilderBlockValidationRequestV2) *types.Block {
+		block, err := engine.ExecutionPayloadV2ToBlock(req.ExecutionPayload)
+		require.NoError(t, err)
+		return block
+	}
+
+	valid := toBlock(buildTestRequestV2(t, bc, lastBlock, types.Transactions{transfer}, nil, common.Big0))
+	failed, err := api.findFailedTransaction(context.Background(), valid, true)
+	require.NoError(t, err)
+	require.Nil(t, failed)
+
+	reverting := toBlock(buildTestRequestV2(t, bc, lastBlock, types.Transactions{transfer, revert}, nil, common.Big0))
+	failed, err = api.findFailedTransaction(context.Background(), reverting, true)
+	require.NoError(t, err)
+	require.NotNil(t, failed)
+	require.Equal(t, 1, failed.index)
+	require.Equal(t, revert.Hash(), failed.tx.Hash())
+	require.ErrorIs(t, failed.err, vm.ErrExecutionReverted)
+	require.Contains(t, failed.trace, "execution reverted")
+
+	// The transfer is only valid with the expected nonce.
+	outOfOrder := valid.WithBody(types.Transactions{revert, transfer}, nil)
+	failed, err = api.findFailedTransaction(context.Background(), outOfOrder, true)
+	require.NoError(t, err)
+	require.NotNil(t, failed)
+	require.Equal(t, 0, failed.index)
+	require.ErrorIs(t, failed.err, core.ErrNonceTooHigh)
+
+	// Tracing does not change the returned error.
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{transfer}, nil, big.NewInt(21000*baseFee.Int64()+1))
+	withoutTracing := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true})
+	require.Equal(t, withoutTracing.ValidateBuilderSubmissionV2(context.Background(), req), api.ValidateBuilderSubmissionV2(context.Background(), req))
+}
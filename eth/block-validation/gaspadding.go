@@ -0,0 +1,91 @@
+package blockvalidation
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// isGasPadded reports whether the gas limit exceeds the estimate by more than 10%.
+func isGasPadded(gasLimit, estimate uint64) bool {
+	return gasLimit > estimate && gasLimit-estimate > estimate/10
+}
+
+// checkGasPadding replays the transactions of the block on top of its parent state and estimates
+// the gas each of them requires. Transactions declaring more than 10% above their estimate are
+// logged, and rejected with ErrGasPadding if enforce is set.
+func (api *BlockValidationAPI) checkGasPadding(block *types.Block, enforce bool) error {
+	parent := api.chain.GetHeaderByHash(block.ParentHash())
+	if parent == nil {
+		return fmt.Errorf("%w %s", ErrUnknownParent, block.ParentHash())
+	}
+	statedb, err := api.chain.StateAt(parent.Root)
+	if err != nil {
+		return err
+	}
+
+	config := api.chain.Config()
+	signer := types.MakeSigner(config, block.Number())
+	blockContext := core.NewEVMBlockContext(block.Header(), api.chain, nil)
+	gp := new(core.GasPool).AddGas(block.GasLimit())
+	for i, tx := range block.Transactions() {
+		msg, err := core.TransactionToMessage(tx, signer, block.BaseFee())
+		if err != nil {
+			return err
+		}
+		statedb.SetTxContext(tx.Hash(), i)
+		estimate, err := estimateGas(blockContext, statedb, config, msg, block.GasLimit())
+		if err != nil {
+			return fmt.Errorf("could not estimate gas of tx %d [%s]: %w", i, tx.Hash(), err)
+		}
+		if estimate != 0 && isGasPadded(tx.Gas(), estimate) {
+			log.Warn("transaction gas limit exceeds estimate", "tx", tx.Hash(), "gas", tx.Gas(), "estimate", estimate)
+			if enforce {
+				return ErrGasPadding{TxHash: tx.Hash(), Gas: tx.Gas(), Estimate: estimate}
+			}
+		}
+
+		evm := vm.NewEVM(blockContext, core.NewEVMTxContext(msg), statedb, config, vm.Config{})
+		if _, err := core.ApplyMessage(evm, msg, gp); err != nil {
+			return fmt.Errorf("could not apply tx %d [%s]: %w", i, tx.Hash(), err)
+		}
+		statedb.Finalise(true)
+	}
+	return nil
+}
+
+// estimateGas binary searches the lowest gas limit the message executes successfully with,
+// without modifying the state. Zero is returned for messages failing with their own gas limit.
+func estimateGas(blockContext vm.BlockContext, statedb *state.StateDB, config *params.ChainConfig, msg *core.Message, blockGasLimit uint64) (uint64, error) {
+	execute := func(gas uint64) (*core.ExecutionResult, error) {
+		trial := *msg
+		trial.GasLimit = gas
+		evm := vm.NewEVM(blockContext, core.NewEVMTxContext(&trial), statedb.Copy(), config, vm.Config{})
+		return core.ApplyMessage(evm, &trial, new(core.GasPool).AddGas(blockGasLimit))
+	}
+
+	result, err := execute(msg.GasLimit)
+	if err != nil {
+		return 0, err
+	}
+	if result.Failed() {
+		return 0, nil
+	}
+	// Refunds are subtracted from the gas used, so the gas limit required is at least the gas used.
+	lo, hi := result.UsedGas-1, msg.GasLimit
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		result, err := execute(mid)
+		if err != nil || result.Failed() {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi, nil
+}
@@ -0,0 +1,108 @@
+package blockvalidation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// CircuitBreakerConfig stops validations while the head of the chain is stale, as blocks would be
+// validated against outdated state.
+type CircuitBreakerConfig struct {
+	// Maximum age of the head block in seconds before submissions are rejected, disabled if zero.
+	MaxHeadAgeSeconds int
+	// Minimum time the circuit stays open once the head is stale.
+	CooldownPeriod time.Duration
+}
+
+// headBreaker opens when the head block is older than the maximum age. It closes again once a
+// new head that is recent enough is seen and the cooldown period has passed.
+type headBreaker struct {
+	maxAge   time.Duration
+	cooldown time.Duration
+	interval time.Duration
+	head     func() *types.Header
+	now      func() time.Time
+
+	mu         sync.Mutex
+	open       bool
+	openedAt   time.Time
+	openedHead common.Hash
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newHeadBreaker(cfg CircuitBreakerConfig, interval time.Duration, head func() *types.Header) *headBreaker {
+	return &headBreaker{
+		maxAge:   time.Duration(cfg.MaxHeadAgeSeconds) * time.Second,
+		cooldown: cfg.CooldownPeriod,
+		interval: interval,
+		head:     head,
+		now:      time.Now,
+		quit:     make(chan struct{}),
+	}
+}
+
+// allow returns ErrCircuitOpen while the circuit is open.
+func (b *headBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.open {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// update checks the age of the current head block.
+func (b *headBreaker) update() {
+	head := b.head()
+	if head == nil {
+		return
+	}
+	now := b.now()
+	stale := now.Sub(time.Unix(int64(head.Time), 0)) > b.maxAge
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case !b.open && stale:
+		log.Warn("head is stale, rejecting submissions", "number", head.Number, "hash", head.Hash(), "time", head.Time)
+		b.open, b.openedAt, b.openedHead = true, now, head.Hash()
+	case b.open && !stale && head.Hash() != b.openedHead && now.Sub(b.openedAt) >= b.cooldown:
+		log.Info("head advanced, accepting submissions", "number", head.Number, "hash", head.Hash())
+		b.open = false
+	}
+}
+
+// Start implements node.Lifecycle, starting to poll the head block.
+func (b *headBreaker) Start() error {
+	b.update()
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ticker := time.NewTicker(b.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.update()
+			case <-b.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop implements node.Lifecycle, stopping the polling.
+func (b *headBreaker) Stop() error {
+	close(b.quit)
+	b.wg.Wait()
+	return nil
+}
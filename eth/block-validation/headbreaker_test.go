@@ -0,0 +1,72 @@
+package blockvalidation
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeadBreaker(t *testing.T) {
+	head := &types.Header{Number: big.NewInt(1), Time: 1000}
+	b := newHeadBreaker(CircuitBreakerConfig{MaxHeadAgeSeconds: 24, CooldownPeriod: time.Minute}, time.Second, func() *types.Header { return head })
+	now := time.Unix(1000, 0)
+	b.now = func() time.Time { return now }
+
+	b.update()
+	require.NoError(t, b.allow())
+
+	now = now.Add(25 * time.Second)
+	b.update()
+	require.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	// A recent head closes it only once the cooldown has passed.
+	head = &types.Header{Number: big.NewInt(2), Time: uint64(now.Unix())}
+	b.update()
+	require.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	// The head that opened it does not close it after the cooldown either.
+	now = now.Add(time.Minute)
+	head = &types.Header{Number: big.NewInt(1), Time: 1000}
+	b.update()
+	require.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	head = &types.Header{Number: big.NewInt(3), Time: uint64(now.Unix())}
+	b.update()
+	require.NoError(t, b.allow())
+}
+
+func TestValidateBuilderSubmissionV2_StaleHead(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, CircuitBreaker: CircuitBreakerConfig{MaxHeadAgeSeconds: 24}})
+	now := time.Unix(int64(lastBlock.Time()), 0)
+	api.headBreaker.now = func() time.Time { return now }
+
+	now = now.Add(time.Minute)
+	api.headBreaker.update()
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrCircuitOpen)
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV1(context.Background(), &BuilderBlockValidationRequest{}), ErrCircuitOpen)
+
+	// The head did not advance, the circuit stays open even once the head looks recent.
+	now = time.Unix(int64(lastBlock.Time()), 0)
+	api.headBreaker.update()
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrCircuitOpen)
+}
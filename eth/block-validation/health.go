@@ -0,0 +1,45 @@
+package blockvalidation
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// defaultMinPeers is used when BlockValidationConfig.MinPeers is not set.
+const defaultMinPeers = 1
+
+// HealthResponse reports whether the node is synced and ready to validate submissions.
+type HealthResponse struct {
+	Synced          bool        `json:"synced"`
+	HeadNumber      uint64      `json:"head_number"`
+	HeadHash        common.Hash `json:"head_hash"`
+	PeerCount       int         `json:"peer_count"`
+	ReadyToValidate bool        `json:"ready_to_validate"`
+}
+
+// Health reports the sync state of the node, as a cheap probe for load balancers. Without an eth
+// service, e.g. when backed by a snapshot, the node is never reported synced.
+func (api *BlockValidationAPI) Health() *HealthResponse {
+	head := api.chain.CurrentBlock()
+	response := &HealthResponse{
+		Synced:     api.eth != nil && api.eth.Synced(),
+		HeadNumber: head.Number.Uint64(),
+		HeadHash:   head.Hash(),
+	}
+	if api.peerCount != nil {
+		response.PeerCount = api.peerCount()
+	}
+	minPeers := api.cfg.MinPeers
+	if minPeers <= 0 {
+		minPeers = defaultMinPeers
+	}
+	response.ReadyToValidate = isReadyToValidate(response.Synced, response.PeerCount, minPeers, head.Time, time.Now(), api.slotDuration())
+	return response
+}
+
+// isReadyToValidate reports whether a node is synced, has more than minPeers peers and a head
+// block no older than two slots.
+func isReadyToValidate(synced bool, peers, minPeers int, headTime uint64, now time.Time, slot time.Duration) bool {
+	return synced && peers > minPeers && now.Sub(time.Unix(int64(headTime), 0)) <= 2*slot
+}
@@ -0,0 +1,50 @@
+package blockvalidation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsReadyToValidate(t *testing.T) {
+	now := time.Unix(1000, 0)
+	slot := 12 * time.Second
+
+	require.True(t, isReadyToValidate(true, 2, 1, 1000, now, slot))
+	require.True(t, isReadyToValidate(true, 3, 1, 1000-24, now, slot))
+	require.False(t, isReadyToValidate(false, 2, 1, 1000, now, slot))
+	require.False(t, isReadyToValidate(true, 1, 1, 1000, now, slot))
+	require.False(t, isReadyToValidate(true, 2, 2, 1000, now, slot))
+	require.False(t, isReadyToValidate(true, 2, 1, 1000-25, now, slot))
+}
+
+func TestHealth(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	defer n.Close()
+
+	head := preMergeBlocks[len(preMergeBlocks)-1]
+	// The test chain is old, stretch the slots so that its head is recent enough.
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{SlotDuration: time.Since(time.Unix(int64(head.Time()), 0))})
+	api.peerCount = func() int { return 2 }
+	health := api.Health()
+	require.True(t, health.Synced)
+	require.Equal(t, head.NumberU64(), health.HeadNumber)
+	require.Equal(t, head.Hash(), health.HeadHash)
+	require.Equal(t, 2, health.PeerCount)
+	require.True(t, health.ReadyToValidate)
+
+	api.peerCount = nil
+	health = api.Health()
+	require.Zero(t, health.PeerCount)
+	require.False(t, health.ReadyToValidate)
+
+	// Without an eth service, as with a snapshot backed API, the node is not reported synced.
+	api.peerCount = func() int { return 2 }
+	api.eth = nil
+	health = api.Health()
+	require.False(t, health.Synced)
+	require.Equal(t, head.NumberU64(), health.HeadNumber)
+	require.False(t, health.ReadyToValidate)
+}
@@ -0,0 +1,87 @@
+package blockvalidation
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ValidationOutcome describes the result of a validation, as passed to the PostValidation hook.
+type ValidationOutcome struct {
+	// Block converted from the execution payload, nil if the conversion failed.
+	Block *types.Block
+	// Result of the EVM replay, nil unless the block was executed successfully.
+	Result   *core.PayloadValidationResult
+	Duration time.Duration
+	// Err is the validation error, always nil for outcomes passed to PostValidation.
+	Err error
+}
+
+// ValidationHooks are callbacks invoked around a validation. The request is a
+// *BuilderBlockValidationRequest or *BuilderBlockValidationRequestV2. All hooks are optional.
+type ValidationHooks struct {
+	// PreValidation runs before validation starts, returning an error rejects the request.
+	PreValidation func(req interface{}) error
+	// PostValidation runs after the request was found to be valid.
+	PostValidation func(req interface{}, result ValidationOutcome)
+	// OnError runs after the request was rejected, including by PreValidation.
+	OnError func(req interface{}, err error)
+}
+
+// CompositeHooks combines hooks so that each of them is invoked in order. PreValidation
+// stops at, and returns, the first error.
+func CompositeHooks(hooks ...ValidationHooks) ValidationHooks {
+	return ValidationHooks{
+		PreValidation: func(req interface{}) error {
+			for _, h := range hooks {
+				if h.PreValidation != nil {
+					if err := h.PreValidation(req); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		PostValidation: func(req interface{}, result ValidationOutcome) {
+			for _, h := range hooks {
+				if h.PostValidation != nil {
+					h.PostValidation(req, result)
+				}
+			}
+		},
+		OnError: func(req interface{}, err error) {
+			for _, h := range hooks {
+				if h.OnError != nil {
+					h.OnError(req, err)
+				}
+			}
+		},
+	}
+}
+
+func (h ValidationHooks) preValidation(req interface{}) error {
+	if h.PreValidation == nil {
+		return nil
+	}
+	if err := h.PreValidation(req); err != nil {
+		h.onError(req, err)
+		return err
+	}
+	return nil
+}
+
+// finish invokes PostValidation or OnError depending on the validation error.
+func (h ValidationHooks) finish(req interface{}, outcome ValidationOutcome, err error) {
+	if err != nil {
+		h.onError(req, err)
+	} else if h.PostValidation != nil {
+		h.PostValidation(req, outcome)
+	}
+}
+
+func (h ValidationHooks) onError(req interface{}, err error) {
+	if h.OnError != nil {
+		h.OnError(req, err)
+	}
+}
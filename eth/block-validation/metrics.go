@@ -0,0 +1,13 @@
+package blockvalidation
+
+import (
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	beaconClientErrorsCounter         = metrics.NewRegisteredCounter("flashbots/beacon_client_errors_total", nil)
+	validationEventsDroppedCounter    = metrics.NewRegisteredCounter("flashbots/validation_events_dropped_total", nil)
+	builderConsecutiveFailuresCounter = metrics.NewRegisteredCounter("flashbots/builder_consecutive_failures_total", nil)
+	privateTxRatioGauge               = metrics.NewRegisteredGaugeFloat64("flashbots/private_tx_ratio", nil)
+	replayDiskReadBytesGauge          = metrics.NewRegisteredGauge("flashbots/replay_disk_read_bytes", nil)
+)
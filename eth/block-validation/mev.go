@@ -0,0 +1,34 @@
+package blockvalidation
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// MEVEvent is an MEV extraction identified in a block, such as "sandwich" or "liquidation".
+type MEVEvent struct {
+	Type   string
+	TxHash common.Hash
+}
+
+// MEVClassifier identifies the MEV extracted by the transactions of a replayed block.
+type MEVClassifier interface {
+	Classify(block *types.Block, receipts types.Receipts) []MEVEvent
+}
+
+// checkMEVPolicy rejects blocks the classifier finds MEV of a blocked type in.
+func checkMEVPolicy(classifier MEVClassifier, blockedTypes []string, block *types.Block, receipts types.Receipts) error {
+	if classifier == nil || len(blockedTypes) == 0 {
+		return nil
+	}
+	blocked := make(map[string]struct{}, len(blockedTypes))
+	for _, t := range blockedTypes {
+		blocked[t] = struct{}{}
+	}
+	for _, event := range classifier.Classify(block, receipts) {
+		if _, found := blocked[event.Type]; found {
+			return ErrBlockedMEVType{EventType: event.Type, TxHash: event.TxHash}
+		}
+	}
+	return nil
+}
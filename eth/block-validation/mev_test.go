@@ -0,0 +1,33 @@
+package blockvalidation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testMEVClassifier []MEVEvent
+
+func (c testMEVClassifier) Classify(block *types.Block, receipts types.Receipts) []MEVEvent {
+	return c
+}
+
+func TestCheckMEVPolicy(t *testing.T) {
+	block := types.NewBlockWithHeader(&types.Header{})
+	classifier := testMEVClassifier{
+		{Type: "arbitrage", TxHash: common.Hash{0x01}},
+		{Type: "sandwich", TxHash: common.Hash{0x02}},
+	}
+
+	require.NoError(t, checkMEVPolicy(nil, []string{"sandwich"}, block, nil))
+	require.NoError(t, checkMEVPolicy(classifier, nil, block, nil))
+	require.NoError(t, checkMEVPolicy(classifier, []string{"liquidation"}, block, nil))
+
+	err := checkMEVPolicy(classifier, []string{"liquidation", "sandwich"}, block, nil)
+	var mevErr ErrBlockedMEVType
+	require.True(t, errors.As(err, &mevErr))
+	require.Equal(t, ErrBlockedMEVType{EventType: "sandwich", TxHash: common.Hash{0x02}}, mevErr)
+}
@@ -0,0 +1,170 @@
+//go:build none
+// +build none
+
+/*
+The mkschema tool writes the JSON Schemas of the block validation requests to schema_v1.json and
+schema_v2.json. The schemas are inferred from the JSON encoding of a sample request, so that the
+custom encodings of the builder API types are captured.
+
+	go run mkschema.go
+*/
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	bellatrixapi "github.com/attestantio/go-builder-client/api/bellatrix"
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	blockvalidation "github.com/ethereum/go-ethereum/eth/block-validation"
+	"github.com/holiman/uint256"
+)
+
+func main() {
+	v1 := &blockvalidation.BuilderBlockValidationRequest{
+		SubmitBlockRequest: bellatrixapi.SubmitBlockRequest{
+			Message:          &apiv1.BidTrace{Value: uint256.NewInt(1)},
+			ExecutionPayload: &bellatrix.ExecutionPayload{Transactions: []bellatrix.Transaction{{0x01}}},
+		},
+		RegisteredGasLimit: 1,
+	}
+	v2 := &blockvalidation.BuilderBlockValidationRequestV2{
+		SubmitBlockRequest: capellaapi.SubmitBlockRequest{
+			Message: &apiv1.BidTrace{Value: uint256.NewInt(1)},
+			ExecutionPayload: &capella.ExecutionPayload{
+				Transactions: []bellatrix.Transaction{{0x01}},
+				Withdrawals:  []*capella.Withdrawal{{}},
+			},
+		},
+		RegisteredGasLimit:      1,
+		ExtraEIPs:               []int{1},
+		WithdrawalListSignature: hexutil.Bytes{0x01},
+		VMConfigOverrides:       &blockvalidation.VMConfigOverrides{},
+	}
+	write("schema_v1.json", "BuilderBlockValidationRequest", v1, &v1.SubmitBlockRequest)
+	write("schema_v2.json", "BuilderBlockValidationRequestV2", v2, &v2.SubmitBlockRequest)
+}
+
+func write(file, title string, request, submission interface{}) {
+	schema, err := requestSchema(request, submission)
+	if err != nil {
+		fatalf("%s: %v", title, err)
+	}
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = title
+
+	out, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		fatalf("%s: %v", title, err)
+	}
+	if err := os.WriteFile(file, append(out, '\n'), 0644); err != nil {
+		fatalf("%v", err)
+	}
+}
+
+// requestSchema infers the schema of a request embedding a builder API submission. The
+// submission has its own JSON encoding, the other fields of the request are added next to it.
+func requestSchema(request, submission interface{}) (map[string]interface{}, error) {
+	sample, err := decode(submission)
+	if err != nil {
+		return nil, err
+	}
+	fields := sample.(map[string]interface{})
+	required := make(map[string]bool, len(fields))
+	for name := range fields {
+		required[name] = true
+	}
+
+	rv := reflect.ValueOf(request).Elem()
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		if field.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		value, err := decode(rv.Field(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", field.Name, err)
+		}
+		if strings.Contains(opts, "string") {
+			value = fmt.Sprint(value)
+		}
+		fields[name] = value
+		required[name] = !strings.Contains(opts, "omitempty")
+	}
+
+	schema := infer(fields)
+	names := make([]string, 0)
+	for name, ok := range required {
+		if ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	schema["required"] = names
+	return schema, nil
+}
+
+func decode(v interface{}) (interface{}, error) {
+	enc, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(enc))
+	dec.UseNumber()
+	var out interface{}
+	return out, dec.Decode(&out)
+}
+
+// infer returns the schema of a decoded JSON sample. All object fields are required, strings
+// are either hex or decimal numbers.
+func infer(sample interface{}) map[string]interface{} {
+	switch v := sample.(type) {
+	case map[string]interface{}:
+		properties := make(map[string]interface{}, len(v))
+		required := make([]string, 0, len(v))
+		for name, value := range v {
+			properties[name] = infer(value)
+			required = append(required, name)
+		}
+		sort.Strings(required)
+		return map[string]interface{}{"type": "object", "properties": properties, "required": required}
+	case []interface{}:
+		schema := map[string]interface{}{"type": "array"}
+		if len(v) > 0 {
+			schema["items"] = infer(v[0])
+		}
+		return schema
+	case string:
+		schema := map[string]interface{}{"type": "string"}
+		if strings.HasPrefix(v, "0x") {
+			schema["pattern"] = "^0x[0-9a-fA-F]*$"
+		} else if strings.Trim(v, "0123456789") == "" {
+			schema["pattern"] = "^[0-9]+$"
+		}
+		return schema
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			return map[string]interface{}{"type": "number"}
+		}
+		return map[string]interface{}{"type": "integer"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "mkschema: "+format+"\n", args...)
+	os.Exit(1)
+}
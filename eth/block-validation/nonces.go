@@ -0,0 +1,116 @@
+package blockvalidation
+
+import (
+	"sync"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// nonceSlotsRetained is how many slots behind the latest one the nonces of senders that did not
+// appear in blocks of the builder since are kept for.
+const nonceSlotsRetained = 32
+
+type builderSender struct {
+	builder phase0.BLSPubKey
+	sender  common.Address
+}
+
+// senderNonces are the highest nonces of a sender in the blocks of a builder, for the latest
+// slot and for all slots before it.
+type senderNonces struct {
+	slot     uint64
+	nonce    uint64
+	previous uint64
+	hasPrev  bool
+}
+
+// nonceTracker tracks the highest nonce each sender used in the validated blocks of a builder.
+// Builders submit many blocks per slot that reuse the same nonces, so a block is only compared
+// against the blocks of earlier slots.
+type nonceTracker struct {
+	mu      sync.Mutex
+	highest uint64
+	nonces  map[builderSender]*senderNonces
+}
+
+func newNonceTracker() *nonceTracker {
+	return &nonceTracker{nonces: make(map[builderSender]*senderNonces)}
+}
+
+// lastSeen returns the highest nonce of the sender in blocks of the builder before the slot.
+func (n *senderNonces) lastSeen(slot uint64) (uint64, bool) {
+	switch {
+	case n.slot < slot:
+		if n.hasPrev && n.previous > n.nonce {
+			return n.previous, true
+		}
+		return n.nonce, true
+	case n.slot == slot:
+		return n.previous, n.hasPrev
+	default:
+		// A late submission for an old slot.
+		return 0, false
+	}
+}
+
+// check verifies that every tracked sender uses higher nonces than in earlier slots.
+func (t *nonceTracker) check(builder phase0.BLSPubKey, slot uint64, signer types.Signer, txs types.Transactions) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, tx := range txs {
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			return err
+		}
+		nonces, ok := t.nonces[builderSender{builder, sender}]
+		if !ok {
+			continue
+		}
+		if last, ok := nonces.lastSeen(slot); ok && tx.Nonce() < last+1 {
+			return ErrNonceRegression{Sender: sender, Expected: last + 1, Got: tx.Nonce()}
+		}
+	}
+	return nil
+}
+
+// record tracks the nonces of the transactions in a validated block of the builder. Blocks for
+// slots after maxSlot are not tracked, and the nonces of slots more than nonceSlotsRetained
+// behind the latest one are dropped.
+func (t *nonceTracker) record(builder phase0.BLSPubKey, slot, maxSlot uint64, signer types.Signer, txs types.Transactions) {
+	if slot > maxSlot {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if slot > t.highest {
+		t.highest = slot
+		for key, nonces := range t.nonces {
+			if t.highest-nonces.slot > nonceSlotsRetained {
+				delete(t.nonces, key)
+			}
+		}
+	}
+	for _, tx := range txs {
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			continue
+		}
+		key := builderSender{builder, sender}
+		nonces, ok := t.nonces[key]
+		switch {
+		case !ok:
+			t.nonces[key] = &senderNonces{slot: slot, nonce: tx.Nonce()}
+		case slot > nonces.slot:
+			if last, ok := nonces.lastSeen(slot); ok {
+				nonces.previous, nonces.hasPrev = last, true
+			}
+			nonces.slot, nonces.nonce = slot, tx.Nonce()
+		case slot == nonces.slot && tx.Nonce() > nonces.nonce:
+			nonces.nonce = tx.Nonce()
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package blockvalidation
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceTracker(t *testing.T) {
+	tracker := newNonceTracker()
+	builder := phase0.BLSPubKey{0x01}
+	tx := func(nonce uint64) types.Transactions {
+		return types.Transactions{signTestTx(t, &types.LegacyTx{Nonce: nonce, To: &common.Address{0x19}, Gas: 21000, GasPrice: big.NewInt(params.InitialBaseFee)})}
+	}
+
+	// Untracked senders are not checked.
+	require.NoError(t, tracker.check(builder, 10, testSigner, tx(5)))
+	tracker.record(builder, 10, math.MaxUint64, testSigner, tx(5))
+
+	// Blocks of the same slot may reuse the nonce.
+	require.NoError(t, tracker.check(builder, 10, testSigner, tx(5)))
+	tracker.record(builder, 10, math.MaxUint64, testSigner, tx(6))
+
+	// Later slots must use higher nonces than all blocks of earlier slots.
+	err := tracker.check(builder, 11, testSigner, tx(6))
+	var nonceErr ErrNonceRegression
+	require.True(t, errors.As(err, &nonceErr))
+	require.Equal(t, ErrNonceRegression{Sender: testAddr, Expected: 7, Got: 6}, nonceErr)
+	require.NoError(t, tracker.check(builder, 11, testSigner, tx(7)))
+	tracker.record(builder, 11, math.MaxUint64, testSigner, tx(7))
+	require.NoError(t, tracker.check(builder, 11, testSigner, tx(7)))
+	require.Error(t, tracker.check(builder, 11, testSigner, tx(6)))
+
+	// Nonces are tracked per builder.
+	require.NoError(t, tracker.check(phase0.BLSPubKey{0x02}, 12, testSigner, tx(0)))
+
+	// Blocks for slots after the slot of the chain head are not tracked.
+	tracker.record(builder, math.MaxUint64, 12, testSigner, tx(100))
+	require.NoError(t, tracker.check(builder, 12, testSigner, tx(8)))
+
+	// Senders that did not appear in the retained slots are dropped.
+	other := phase0.BLSPubKey{0x03}
+	tracker.record(other, 11+nonceSlotsRetained, math.MaxUint64, testSigner, tx(0))
+	require.Contains(t, tracker.nonces, builderSender{builder, testAddr})
+	tracker.record(other, 12+nonceSlotsRetained, math.MaxUint64, testSigner, tx(1))
+	require.NotContains(t, tracker.nonces, builderSender{builder, testAddr})
+	require.NoError(t, tracker.check(builder, 13+nonceSlotsRetained, testSigner, tx(0)))
+}
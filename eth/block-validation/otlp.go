@@ -0,0 +1,254 @@
+package blockvalidation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	otlpPushInterval = 30 * time.Second
+	otlpScopeName    = "github.com/ethereum/go-ethereum/eth/block-validation"
+)
+
+// otlpSummary accumulates the count and sum of an observed value.
+type otlpSummary struct {
+	count uint64
+	sum   float64
+}
+
+func (s *otlpSummary) observe(v float64) {
+	s.count++
+	s.sum += v
+}
+
+// otlpExporter aggregates validation metrics in process and periodically pushes them to an
+// OTLP collector using the OTLP/HTTP JSON encoding. All metrics are cumulative since start.
+type otlpExporter struct {
+	endpoint string
+	client   *http.Client
+
+	mu             sync.Mutex
+	start          time.Time
+	count          uint64
+	duration       otlpSummary // milliseconds
+	profitDeclared otlpSummary // gwei
+	profitMeasured otlpSummary // gwei
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newOTLPExporter(endpoint string) *otlpExporter {
+	// The OTLP/HTTP receiver listens on /v1/metrics unless the endpoint is configured explicitly.
+	if u, err := url.Parse(endpoint); err == nil && (u.Path == "" || u.Path == "/") {
+		u.Path = "/v1/metrics"
+		endpoint = u.String()
+	}
+	return &otlpExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		start:    time.Now(),
+		quit:     make(chan struct{}),
+	}
+}
+
+func weiToGwei(wei *big.Int) float64 {
+	gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.GWei)).Float64()
+	return gwei
+}
+
+// observe records the outcome of a single validation.
+func (e *otlpExporter) observe(duration time.Duration, msg *apiv1.BidTrace, result *core.PayloadValidationResult, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.count++
+	e.duration.observe(float64(duration) / float64(time.Millisecond))
+	if msg != nil && msg.Value != nil {
+		e.profitDeclared.observe(weiToGwei(msg.Value.ToBig()))
+	}
+	if err == nil && result != nil {
+		e.profitMeasured.observe(weiToGwei(result.FeeRecipientBalanceDelta))
+	}
+}
+
+// Start implements node.Lifecycle, starting the periodic push.
+func (e *otlpExporter) Start() error {
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		ticker := time.NewTicker(otlpPushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := e.export(); err != nil {
+					log.Warn("failed to export validation metrics", "endpoint", e.endpoint, "err", err)
+				}
+			case <-e.quit:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop implements node.Lifecycle, stopping the periodic push.
+func (e *otlpExporter) Stop() error {
+	close(e.quit)
+	e.wg.Wait()
+	return nil
+}
+
+// export pushes the current values of all metrics to the collector.
+func (e *otlpExporter) export() error {
+	body, err := json.Marshal(e.snapshot(time.Now()))
+	if err != nil {
+		return err
+	}
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// The types below are the subset of the OTLP metrics protobuf messages that is needed,
+// in their canonical JSON mapping (64 bit integers are encoded as strings).
+
+type otlpExportRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     otlpResource       `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpScopeMetrics struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Metrics []otlpMetric `json:"metrics"`
+}
+
+type otlpMetric struct {
+	Name      string         `json:"name"`
+	Unit      string         `json:"unit,omitempty"`
+	Sum       *otlpSum       `json:"sum,omitempty"`
+	Histogram *otlpHistogram `json:"histogram,omitempty"`
+}
+
+// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE.
+const otlpCumulative = 2
+
+type otlpSum struct {
+	DataPoints             []otlpNumberDataPoint `json:"dataPoints"`
+	AggregationTemporality int                   `json:"aggregationTemporality"`
+	IsMonotonic            bool                  `json:"isMonotonic"`
+}
+
+type otlpNumberDataPoint struct {
+	StartTimeUnixNano string `json:"startTimeUnixNano"`
+	TimeUnixNano      string `json:"timeUnixNano"`
+	AsInt             string `json:"asInt"`
+}
+
+type otlpHistogram struct {
+	DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+	AggregationTemporality int                      `json:"aggregationTemporality"`
+}
+
+type otlpHistogramDataPoint struct {
+	StartTimeUnixNano string   `json:"startTimeUnixNano"`
+	TimeUnixNano      string   `json:"timeUnixNano"`
+	Count             string   `json:"count"`
+	Sum               float64  `json:"sum"`
+	BucketCounts      []string `json:"bucketCounts"`
+	ExplicitBounds    []string `json:"explicitBounds"`
+}
+
+func (e *otlpExporter) snapshot(now time.Time) *otlpExportRequest {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	startNano := strconv.FormatInt(e.start.UnixNano(), 10)
+	nowNano := strconv.FormatInt(now.UnixNano(), 10)
+	histogram := func(name, unit string, s otlpSummary) otlpMetric {
+		count := strconv.FormatUint(s.count, 10)
+		return otlpMetric{
+			Name: name,
+			Unit: unit,
+			Histogram: &otlpHistogram{
+				DataPoints: []otlpHistogramDataPoint{{
+					StartTimeUnixNano: startNano,
+					TimeUnixNano:      nowNano,
+					Count:             count,
+					Sum:               s.sum,
+					BucketCounts:      []string{count},
+					ExplicitBounds:    []string{},
+				}},
+				AggregationTemporality: otlpCumulative,
+			},
+		}
+	}
+
+	var resource otlpResource
+	resource.Attributes = make([]otlpAttribute, 1)
+	resource.Attributes[0].Key = "service.name"
+	resource.Attributes[0].Value.StringValue = "geth-block-validation"
+
+	var scope otlpScopeMetrics
+	scope.Scope.Name = otlpScopeName
+	scope.Metrics = []otlpMetric{
+		histogram("flashbots.validation.duration", "ms", e.duration),
+		{
+			Name: "flashbots.validation.count",
+			Sum: &otlpSum{
+				DataPoints: []otlpNumberDataPoint{{
+					StartTimeUnixNano: startNano,
+					TimeUnixNano:      nowNano,
+					AsInt:             strconv.FormatUint(e.count, 10),
+				}},
+				AggregationTemporality: otlpCumulative,
+				IsMonotonic:            true,
+			},
+		},
+		histogram("flashbots.validation.profit_declared", "Gwei", e.profitDeclared),
+		histogram("flashbots.validation.profit_measured", "Gwei", e.profitMeasured),
+	}
+
+	return &otlpExportRequest{
+		ResourceMetrics: []otlpResourceMetrics{{
+			Resource:     resource,
+			ScopeMetrics: []otlpScopeMetrics{scope},
+		}},
+	}
+}
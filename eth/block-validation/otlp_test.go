@@ -0,0 +1,55 @@
+package blockvalidation
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	var received otlpExportRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/metrics", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	exporter := newOTLPExporter(server.URL)
+	msg := &apiv1.BidTrace{Value: uint256.NewInt(2_000_000_000)}
+	exporter.observe(10*time.Millisecond, msg, &core.PayloadValidationResult{FeeRecipientBalanceDelta: big.NewInt(3_000_000_000)}, nil)
+	exporter.observe(30*time.Millisecond, msg, nil, errors.New("invalid"))
+	require.NoError(t, exporter.export())
+
+	require.Len(t, received.ResourceMetrics, 1)
+	require.Len(t, received.ResourceMetrics[0].ScopeMetrics, 1)
+	metrics := make(map[string]otlpMetric)
+	for _, m := range received.ResourceMetrics[0].ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	require.Equal(t, "2", metrics["flashbots.validation.count"].Sum.DataPoints[0].AsInt)
+	require.True(t, metrics["flashbots.validation.count"].Sum.IsMonotonic)
+
+	duration := metrics["flashbots.validation.duration"].Histogram.DataPoints[0]
+	require.Equal(t, "2", duration.Count)
+	require.Equal(t, 40.0, duration.Sum)
+
+	declared := metrics["flashbots.validation.profit_declared"].Histogram.DataPoints[0]
+	require.Equal(t, "2", declared.Count)
+	require.Equal(t, 4.0, declared.Sum)
+
+	// Failed validations have no measured profit.
+	measured := metrics["flashbots.validation.profit_measured"].Histogram.DataPoints[0]
+	require.Equal(t, "1", measured.Count)
+	require.Equal(t, 3.0, measured.Sum)
+}
@@ -0,0 +1,90 @@
+package blockvalidation
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	priceOracleMethod = "latestAnswer"
+	priceOracleGas    = 100_000
+)
+
+// priceOracle reads the expected price per unit of gas in wei from latestAnswer() on a price
+// oracle contract, to check that the declared value of a block is denominated in ETH.
+type priceOracle struct {
+	address common.Address
+	abi     abi.ABI
+}
+
+func newPriceOracle(address common.Address, abiJSON string) (*priceOracle, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, fmt.Errorf("invalid price oracle ABI: %w", err)
+	}
+	method, ok := parsed.Methods[priceOracleMethod]
+	if !ok {
+		return nil, fmt.Errorf("price oracle ABI has no %s method", priceOracleMethod)
+	}
+	if len(method.Outputs) != 1 || method.Outputs[0].Type.String() != "int256" {
+		return nil, fmt.Errorf("unexpected price oracle method %s, it must return int256", method.Sig)
+	}
+	return &priceOracle{address: address, abi: parsed}, nil
+}
+
+// latestAnswer calls the oracle in the given state.
+func (o *priceOracle) latestAnswer(blockContext vm.BlockContext, statedb vm.StateDB, config *params.ChainConfig) (*big.Int, error) {
+	input, err := o.abi.Pack(priceOracleMethod)
+	if err != nil {
+		return nil, err
+	}
+	evm := vm.NewEVM(blockContext, vm.TxContext{GasPrice: new(big.Int)}, statedb, config, vm.Config{NoBaseFee: true})
+	ret, _, err := evm.StaticCall(vm.AccountRef(common.Address{}), o.address, input, priceOracleGas)
+	if err != nil {
+		return nil, fmt.Errorf("price oracle call failed: %w", err)
+	}
+	out, err := o.abi.Unpack(priceOracleMethod, ret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price oracle answer: %w", err)
+	}
+	answer, ok := out[0].(*big.Int)
+	if !ok || answer.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid price oracle answer %v", out[0])
+	}
+	return answer, nil
+}
+
+// checkProfitPrice reads the price per gas from the oracle in the parent state of the block and
+// verifies that the declared value is within 10% of the gas used at that price.
+func (api *BlockValidationAPI) checkProfitPrice(block *types.Block, value *big.Int) error {
+	parent := api.chain.GetHeaderByHash(block.ParentHash())
+	if parent == nil {
+		return fmt.Errorf("%w %s", ErrUnknownParent, block.ParentHash())
+	}
+	statedb, err := api.chain.StateAt(parent.Root)
+	if err != nil {
+		return err
+	}
+	price, err := api.priceOracle.latestAnswer(core.NewEVMBlockContext(block.Header(), api.chain, nil), statedb, api.chain.Config())
+	if err != nil {
+		return err
+	}
+	return checkValueWithinTolerance(value, new(big.Int).Mul(price, new(big.Int).SetUint64(block.GasUsed())))
+}
+
+// checkValueWithinTolerance rejects a value deviating from the expected one by more than 10%.
+func checkValueWithinTolerance(value, expected *big.Int) error {
+	deviation := new(big.Int).Sub(value, expected)
+	if deviation.Abs(deviation).Mul(deviation, big.NewInt(10)).Cmp(expected) > 0 {
+		return fmt.Errorf("%w: declared %s wei, expected %s wei", ErrProfitMispriced, value, expected)
+	}
+	return nil
+}
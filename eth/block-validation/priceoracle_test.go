@@ -0,0 +1,68 @@
+package blockvalidation
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+const testPriceOracleABI = `[{"type":"function","name":"latestAnswer","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"int256"}]}]`
+
+func TestNewPriceOracle(t *testing.T) {
+	address := common.Address{0xb0}
+	_, err := newPriceOracle(address, "not json")
+	require.ErrorContains(t, err, "invalid price oracle ABI")
+	_, err = newPriceOracle(address, `[{"type":"function","name":"latestRound","inputs":[],"outputs":[{"name":"","type":"uint80"}]}]`)
+	require.ErrorContains(t, err, "no latestAnswer method")
+	_, err = newPriceOracle(address, `[{"type":"function","name":"latestAnswer","inputs":[],"outputs":[{"name":"","type":"uint256"}]}]`)
+	require.ErrorContains(t, err, "must return int256")
+
+	oracle, err := newPriceOracle(address, testPriceOracleABI)
+	require.NoError(t, err)
+	require.Equal(t, address, oracle.address)
+}
+
+func TestPriceOracleLatestAnswer(t *testing.T) {
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	require.NoError(t, err)
+	address := common.Address{0xb0}
+	// PUSH32 answer PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	answer := common.BigToHash(big.NewInt(30 * params.GWei))
+	code := append(append([]byte{byte(vm.PUSH32)}, answer.Bytes()...), byte(vm.PUSH1), 0, byte(vm.MSTORE), byte(vm.PUSH1), 32, byte(vm.PUSH1), 0, byte(vm.RETURN))
+	statedb.SetCode(address, code)
+
+	blockContext := vm.BlockContext{
+		CanTransfer: core.CanTransfer,
+		Transfer:    core.Transfer,
+		BlockNumber: big.NewInt(1),
+		Difficulty:  common.Big0,
+		BaseFee:     big.NewInt(params.InitialBaseFee),
+	}
+	oracle, err := newPriceOracle(address, testPriceOracleABI)
+	require.NoError(t, err)
+	price, err := oracle.latestAnswer(blockContext, statedb, params.AllEthashProtocolChanges)
+	require.NoError(t, err)
+	require.Equal(t, int64(30*params.GWei), price.Int64())
+
+	// An address without code does not answer.
+	oracle.address = common.Address{0xb1}
+	_, err = oracle.latestAnswer(blockContext, statedb, params.AllEthashProtocolChanges)
+	require.ErrorContains(t, err, "invalid price oracle answer")
+}
+
+func TestCheckValueWithinTolerance(t *testing.T) {
+	expected := big.NewInt(1000)
+	require.NoError(t, checkValueWithinTolerance(big.NewInt(1000), expected))
+	require.NoError(t, checkValueWithinTolerance(big.NewInt(900), expected))
+	require.NoError(t, checkValueWithinTolerance(big.NewInt(1100), expected))
+	require.ErrorIs(t, checkValueWithinTolerance(big.NewInt(899), expected), ErrProfitMispriced)
+	require.ErrorIs(t, checkValueWithinTolerance(big.NewInt(1101), expected), ErrProfitMispriced)
+}
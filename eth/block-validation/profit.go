@@ -0,0 +1,74 @@
+package blockvalidation
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ProfitMode selects how the proposer payment of V2 blocks is verified. If unset, the payment
+// is verified as selected by UseBalanceDiffProfit.
+type ProfitMode string
+
+const (
+	// ProfitModeBalanceDiff accepts an increase of the fee recipient balance by at least the
+	// value, falling back to a payment of the value in the last transaction.
+	ProfitModeBalanceDiff ProfitMode = "balanceDiff"
+	// ProfitModeFeesOnly requires the fee recipient to be the coinbase of the block, and the
+	// priority fees of the block to be at least the value.
+	ProfitModeFeesOnly ProfitMode = "feesOnly"
+	// ProfitModeCoinbaseTransfer requires the last transaction to transfer the value from the
+	// coinbase to the fee recipient, which must not be the coinbase itself.
+	ProfitModeCoinbaseTransfer ProfitMode = "coinbaseTransfer"
+)
+
+func (m ProfitMode) valid() bool {
+	switch m {
+	case "", ProfitModeBalanceDiff, ProfitModeFeesOnly, ProfitModeCoinbaseTransfer:
+		return true
+	}
+	return false
+}
+
+// checkCoinbaseTransfer verifies that the last transaction of the block explicitly pays the
+// value from the coinbase to the fee recipient, and that the fee recipient does not also
+// receive the fees of the block.
+func checkCoinbaseTransfer(block *types.Block, signer types.Signer, feeRecipient common.Address, value *big.Int) error {
+	if block.Coinbase() == feeRecipient {
+		return fmt.Errorf("%w: fee recipient %s is the coinbase", ErrProfitModeViolation, feeRecipient)
+	}
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return fmt.Errorf("%w: no proposer payment transaction", ErrProfitModeViolation)
+	}
+	payment := txs[len(txs)-1]
+	if to := payment.To(); to == nil || *to != feeRecipient {
+		return fmt.Errorf("%w: last transaction not to the fee recipient %s", ErrProfitModeViolation, feeRecipient)
+	}
+	from, err := types.Sender(signer, payment)
+	if err != nil {
+		return err
+	}
+	if from != block.Coinbase() {
+		return fmt.Errorf("%w: proposer payment from %s, not from the coinbase %s", ErrProfitModeViolation, from, block.Coinbase())
+	}
+	if payment.Value().Cmp(value) != 0 {
+		return newValidationError(CodeInsufficientProfit, "inaccurate payment %s, expected %s", payment.Value(), value)
+	}
+	return nil
+}
+
+// checkPriorityFees verifies that the block pays at least the value in priority fees.
+func checkPriorityFees(block *types.Block, receipts types.Receipts, value *big.Int) error {
+	fees := new(big.Int)
+	for i, tx := range block.Transactions() {
+		tip := tx.EffectiveGasTipValue(block.BaseFee())
+		fees.Add(fees, tip.Mul(tip, new(big.Int).SetUint64(receipts[i].GasUsed)))
+	}
+	if fees.Cmp(value) < 0 {
+		return newValidationError(CodeInsufficientProfit, "priority fees %s below the value %s", fees, value)
+	}
+	return nil
+}
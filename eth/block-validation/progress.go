@@ -0,0 +1,102 @@
+package blockvalidation
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/gorilla/websocket"
+)
+
+// progressWebsocketPath is where the V2 validation progress websocket is served on the HTTP endpoint.
+const progressWebsocketPath = "/flashbots/validation"
+
+// Stages of a V2 validation reported to progress websocket clients.
+const (
+	stagePayloadConverted  = "payload_converted"
+	stageFieldChecksPassed = "field_checks_passed"
+	stageEVMStarted        = "evm_started"
+	stageEVMComplete       = "evm_complete"
+	stageProfitVerified    = "profit_verified"
+	stageDone              = "done"
+)
+
+// ValidationProgress is sent to progress websocket clients as a V2 validation passes each stage.
+type ValidationProgress struct {
+	Stage   string `json:"stage"`
+	GasUsed uint64 `json:"gas_used,omitempty"`
+	Profit  string `json:"profit,omitempty"`
+	Valid   *bool  `json:"valid,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type progressKey struct{}
+
+// withProgress returns a context reporting the stages reached by a validation to report.
+func withProgress(ctx context.Context, report func(ValidationProgress)) context.Context {
+	return context.WithValue(ctx, progressKey{}, report)
+}
+
+// reportProgress reports a stage to the progress function of the context, if any.
+func reportProgress(ctx context.Context, progress ValidationProgress) {
+	if report, ok := ctx.Value(progressKey{}).(func(ValidationProgress)); ok {
+		report(progress)
+	}
+}
+
+func newProfitProgress(profit *big.Int) ValidationProgress {
+	progress := ValidationProgress{Stage: stageProfitVerified}
+	if profit != nil {
+		progress.Profit = profit.String()
+	}
+	return progress
+}
+
+// progressHandler serves websocket connections on which every message is a V2 request, answered
+// with the progress of its validation up to a done message.
+type progressHandler struct {
+	api      *BlockValidationAPI
+	upgrader websocket.Upgrader
+}
+
+func (h *progressHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Debug("could not upgrade validation progress connection", "err", err)
+		return
+	}
+	defer conn.Close()
+
+	limit := maxPayloadBytes.Load()
+	if limit <= 0 {
+		limit = defaultMaxPayloadBytes
+	}
+	conn.SetReadLimit(limit)
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var writeErr error
+		send := func(progress ValidationProgress) {
+			if writeErr == nil {
+				writeErr = conn.WriteJSON(progress)
+			}
+		}
+		params := new(BuilderBlockValidationRequestV2)
+		if err = json.Unmarshal(data, params); err == nil {
+			_, _, err = h.api.validateBuilderSubmissionV2(withProgress(r.Context(), send), params, nil)
+		}
+		done := ValidationProgress{Stage: stageDone, Valid: new(bool)}
+		*done.Valid = err == nil
+		if err != nil {
+			done.Error = err.Error()
+		}
+		send(done)
+		if writeErr != nil {
+			return
+		}
+	}
+}
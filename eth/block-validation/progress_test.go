@@ -0,0 +1,73 @@
+package blockvalidation
+
+import (
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressWebsocket(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	profit := big.NewInt(21000 * baseFee.Int64())
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, profit)
+	encoded := fuzzSeedRequest(t, &req.SubmitBlockRequest, map[string]string{
+		"registered_gas_limit": fmt.Sprintf(`"%d"`, req.RegisteredGasLimit),
+		"withdrawals_root":     fmt.Sprintf("%q", req.WithdrawalsRoot.Hex()),
+	})
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true})
+	server := httptest.NewServer(&progressHandler{api: api})
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	receive := func() []ValidationProgress {
+		var stages []ValidationProgress
+		for {
+			var progress ValidationProgress
+			require.NoError(t, conn.ReadJSON(&progress))
+			stages = append(stages, progress)
+			if progress.Stage == stageDone {
+				return stages
+			}
+		}
+	}
+	valid, invalid := true, false
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, encoded))
+	require.Equal(t, []ValidationProgress{
+		{Stage: stagePayloadConverted},
+		{Stage: stageFieldChecksPassed},
+		{Stage: stageEVMStarted},
+		{Stage: stageEVMComplete, GasUsed: 21000},
+		{Stage: stageProfitVerified, Profit: profit.String()},
+		{Stage: stageDone, Valid: &valid},
+	}, receive())
+
+	// The connection is reused for further requests, malformed ones fail right away.
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("{}")))
+	stages := receive()
+	require.Len(t, stages, 1)
+	require.Equal(t, &invalid, stages[0].Valid)
+	require.NotEmpty(t, stages[0].Error)
+}
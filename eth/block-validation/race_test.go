@@ -0,0 +1,69 @@
+//go:build race
+
+package blockvalidation
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConcurrentSubmissionsV2 validates submissions concurrently on the same API, with the
+// checks keeping state between validations enabled. Run it with -race.
+func TestConcurrentSubmissionsV2(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	profit := big.NewInt(21000 * baseFee.Int64())
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, profit)
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{
+		UseBalanceDiffProfit:       true,
+		ValidationCacheSize:        16,
+		RateLimit:                  1000,
+		RateBurst:                  1000,
+		MaxConsecutiveFailures:     10,
+		EnforceNonceMonotonicity:   true,
+		MaxUniqueContractsAccessed: 10,
+	})
+
+	const submissions = 100
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, submissions)
+	)
+	for i := 0; i < submissions; i++ {
+		// Claiming a different value makes every request distinct, so that each is executed.
+		params := *req
+		message := *req.Message
+		message.Value = uint256.MustFromBig(new(big.Int).Sub(profit, big.NewInt(int64(i%10))))
+		params.Message = &message
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = api.ValidateBuilderSubmissionV2(context.Background(), &params)
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		require.NoError(t, err, "submission %d", i)
+	}
+	require.Equal(t, uint64(submissions), api.GetBuilderStats()[req.Message.BuilderPubkey.String()].TotalSubmissions)
+}
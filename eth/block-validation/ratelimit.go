@@ -0,0 +1,103 @@
+package blockvalidation
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// rateLimitedSlotsRetained is how many slots behind the latest one the limiters of builders
+	// that stopped submitting are kept for.
+	rateLimitedSlotsRetained = 32
+	// maxRateLimitedBuilders is how many builders are tracked at most. Submissions of further
+	// builders are rate limited until the limiters of inactive builders expire.
+	maxRateLimitedBuilders = 1 << 14
+)
+
+// ErrRateLimited is returned when a builder submits faster than RateLimit allows.
+type ErrRateLimited struct {
+	Builder phase0.BLSPubKey
+}
+
+func (e ErrRateLimited) Error() string {
+	return fmt.Sprintf("builder %s is rate limited", e.Builder.String())
+}
+
+// ErrorCode returns the JSON-RPC error code for exceeded limits, the equivalent of HTTP 429.
+func (e ErrRateLimited) ErrorCode() int {
+	return -32005
+}
+
+type builderLimiter struct {
+	limiter  *rate.Limiter
+	lastSlot atomic.Uint64
+}
+
+// builderRateLimiter limits the rate of submissions of each builder with a token bucket.
+type builderRateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	limiters sync.Map // hex encoded builder pubkey -> *builderLimiter
+	count    atomic.Int64
+
+	mu      sync.Mutex
+	highest uint64
+}
+
+func newBuilderRateLimiter(limit rate.Limit, burst int) *builderRateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &builderRateLimiter{limit: limit, burst: burst}
+}
+
+// allow reports whether the builder may submit a block for the slot now. Slots after maxSlot
+// are counted as maxSlot, so that submissions for far future slots do not expire the limiters.
+func (l *builderRateLimiter) allow(builder phase0.BLSPubKey, slot, maxSlot uint64) bool {
+	if slot > maxSlot {
+		slot = maxSlot
+	}
+	key := builder.String()
+	value, ok := l.limiters.Load(key)
+	if !ok {
+		if l.count.Load() >= maxRateLimitedBuilders {
+			return false
+		}
+		var loaded bool
+		if value, loaded = l.limiters.LoadOrStore(key, &builderLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}); !loaded {
+			l.count.Add(1)
+		}
+	}
+	entry := value.(*builderLimiter)
+	for {
+		last := entry.lastSlot.Load()
+		if slot <= last || entry.lastSlot.CompareAndSwap(last, slot) {
+			break
+		}
+	}
+	l.expire(slot)
+	return entry.limiter.Allow()
+}
+
+// expire drops the limiters of builders that did not submit in the retained slots.
+func (l *builderRateLimiter) expire(slot uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if slot <= l.highest {
+		return
+	}
+	l.highest = slot
+	l.limiters.Range(func(key, value interface{}) bool {
+		if last := value.(*builderLimiter).lastSlot.Load(); last < slot && slot-last > rateLimitedSlotsRetained {
+			l.limiters.Delete(key)
+			l.count.Add(-1)
+		}
+		return true
+	})
+}
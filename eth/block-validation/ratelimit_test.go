@@ -0,0 +1,62 @@
+package blockvalidation
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestBuilderRateLimiter(t *testing.T) {
+	l := newBuilderRateLimiter(rate.Every(1<<62), 2)
+	a, b, c := phase0.BLSPubKey{0x01}, phase0.BLSPubKey{0x02}, phase0.BLSPubKey{0x03}
+
+	require.True(t, l.allow(a, 1, math.MaxUint64))
+	require.True(t, l.allow(a, 1, math.MaxUint64))
+	require.False(t, l.allow(a, 1, math.MaxUint64))
+
+	// Builders are limited independently.
+	require.True(t, l.allow(b, 1, math.MaxUint64))
+
+	// Limiters of builders that stopped submitting expire.
+	require.True(t, l.allow(c, 1+rateLimitedSlotsRetained, math.MaxUint64))
+	_, ok := l.limiters.Load(a.String())
+	require.True(t, ok)
+	require.True(t, l.allow(c, 2+rateLimitedSlotsRetained, math.MaxUint64))
+	_, ok = l.limiters.Load(a.String())
+	require.False(t, ok)
+	require.True(t, l.allow(a, 2+rateLimitedSlotsRetained, math.MaxUint64))
+
+	// Slots after the slot of the chain head do not expire limiters.
+	require.True(t, l.allow(phase0.BLSPubKey{0x04}, math.MaxUint64, 3+rateLimitedSlotsRetained))
+	_, ok = l.limiters.Load(a.String())
+	require.True(t, ok)
+	require.Equal(t, uint64(3+rateLimitedSlotsRetained), l.highest)
+
+	// Builders beyond the tracked maximum are limited.
+	for i := 0; l.count.Load() < maxRateLimitedBuilders; i++ {
+		l.allow(phase0.BLSPubKey{0xff, byte(i >> 16), byte(i >> 8), byte(i)}, 3+rateLimitedSlotsRetained, math.MaxUint64)
+	}
+	require.False(t, l.allow(phase0.BLSPubKey{0x05}, 3+rateLimitedSlotsRetained, math.MaxUint64))
+}
+
+func TestValidateBuilderSubmissionV2_RateLimit(t *testing.T) {
+	api := newBlockValidationAPI(nil, nil, BlockValidationConfig{RateLimit: rate.Every(1 << 62), RateBurst: 1})
+	req := &BuilderBlockValidationRequestV2{SubmitBlockRequest: capellaapi.SubmitBlockRequest{Message: &apiv1.BidTrace{BuilderPubkey: phase0.BLSPubKey{0x01}}}}
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), &validationErr)
+	require.Equal(t, CodeNilPayload, validationErr.Code)
+
+	err := api.ValidateBuilderSubmissionV2(context.Background(), req)
+	var rateLimitedErr ErrRateLimited
+	require.True(t, errors.As(err, &rateLimitedErr))
+	require.Equal(t, phase0.BLSPubKey{0x01}, rateLimitedErr.Builder)
+	require.Equal(t, -32005, rateLimitedErr.ErrorCode())
+}
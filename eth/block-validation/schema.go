@@ -0,0 +1,24 @@
+package blockvalidation
+
+import (
+	_ "embed"
+)
+
+//go:generate go run mkschema.go
+
+var (
+	//go:embed schema_v1.json
+	requestSchemaV1 []byte
+	//go:embed schema_v2.json
+	requestSchemaV2 []byte
+)
+
+// Schema returns the JSON Schema (draft-07) of the request encoding, for client library authors.
+func (r *BuilderBlockValidationRequest) Schema() ([]byte, error) {
+	return append([]byte(nil), requestSchemaV1...), nil
+}
+
+// Schema returns the JSON Schema (draft-07) of the request encoding, for client library authors.
+func (r *BuilderBlockValidationRequestV2) Schema() ([]byte, error) {
+	return append([]byte(nil), requestSchemaV2...), nil
+}
@@ -0,0 +1,60 @@
+package blockvalidation
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type requestSchema struct {
+	Schema     string                            `json:"$schema"`
+	Type       string                            `json:"type"`
+	Properties map[string]map[string]interface{} `json:"properties"`
+	Required   []string                          `json:"required"`
+}
+
+// jsonFieldCount counts the JSON fields of a request, including those of the embedded submission.
+func jsonFieldCount(t reflect.Type) int {
+	count := 0
+	for i := 0; i < t.NumField(); i++ {
+		if field := t.Field(i); field.Anonymous {
+			count += field.Type.NumField()
+		} else {
+			count++
+		}
+	}
+	return count
+}
+
+func TestRequestSchema(t *testing.T) {
+	tests := []struct {
+		request interface{ Schema() ([]byte, error) }
+		typ     reflect.Type
+	}{
+		{new(BuilderBlockValidationRequest), reflect.TypeOf(BuilderBlockValidationRequest{})},
+		{new(BuilderBlockValidationRequestV2), reflect.TypeOf(BuilderBlockValidationRequestV2{})},
+	}
+	for _, test := range tests {
+		t.Run(test.typ.Name(), func(t *testing.T) {
+			enc, err := test.request.Schema()
+			require.NoError(t, err)
+			var schema requestSchema
+			require.NoError(t, json.Unmarshal(enc, &schema))
+
+			require.Equal(t, "http://json-schema.org/draft-07/schema#", schema.Schema)
+			require.Equal(t, "object", schema.Type)
+			require.Len(t, schema.Properties, jsonFieldCount(test.typ))
+			require.Contains(t, schema.Required, "registered_gas_limit")
+
+			// The gas limit is a decimal number encoded as a string.
+			require.Equal(t, "string", schema.Properties["registered_gas_limit"]["type"])
+			require.Equal(t, "^[0-9]+$", schema.Properties["registered_gas_limit"]["pattern"])
+			for _, name := range []string{"message", "execution_payload"} {
+				require.Equal(t, "object", schema.Properties[name]["type"])
+				require.NotEmpty(t, schema.Properties[name]["properties"])
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package blockvalidation
+
+import (
+	"fmt"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/flashbots/go-boost-utils/ssz"
+)
+
+// builderSigningDomain returns the application builder domain of the chain with the given
+// genesis fork version, over which builders sign their bids.
+func builderSigningDomain(genesisForkVersion phase0.Version) phase0.Domain {
+	return ssz.ComputeDomain(ssz.DomainTypeAppBuilder, genesisForkVersion, phase0.Root{})
+}
+
+// verifyBuilderSignature verifies the BLS signature of the builder over the signing root of the
+// bid message.
+func verifyBuilderSignature(message *apiv1.BidTrace, signature phase0.BLSSignature, domain phase0.Domain) error {
+	if message == nil {
+		return newValidationError(CodeNilMessage, "nil bid message")
+	}
+	ok, err := ssz.VerifySignature(message, domain, message.BuilderPubkey[:], signature[:])
+	if err != nil {
+		return wrapValidationError(CodeInvalidSignature, fmt.Errorf("%w: %v", ErrInvalidBuilderSignature, err))
+	}
+	if !ok {
+		return wrapValidationError(CodeInvalidSignature, ErrInvalidBuilderSignature)
+	}
+	return nil
+}
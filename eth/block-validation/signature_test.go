@@ -0,0 +1,39 @@
+package blockvalidation
+
+import (
+	"testing"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/flashbots/go-boost-utils/bls"
+	"github.com/flashbots/go-boost-utils/ssz"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBuilderSignature(t *testing.T) {
+	sk, pk, err := bls.GenerateNewKeypair()
+	require.NoError(t, err)
+	message := &apiv1.BidTrace{Slot: 1, Value: uint256.NewInt(10)}
+	copy(message.BuilderPubkey[:], bls.PublicKeyToBytes(pk))
+
+	domain := builderSigningDomain(phase0.Version{})
+	signature, err := ssz.SignMessage(message, domain, sk)
+	require.NoError(t, err)
+	require.NoError(t, verifyBuilderSignature(message, signature, domain))
+
+	// The signature is only valid in the domain of the chain.
+	err = verifyBuilderSignature(message, signature, builderSigningDomain(phase0.Version{0x01}))
+	require.ErrorIs(t, err, ErrInvalidBuilderSignature)
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	require.Equal(t, CodeInvalidSignature, verr.Code)
+
+	// The signature does not cover a different message.
+	tampered := *message
+	tampered.Value = uint256.NewInt(11)
+	require.ErrorIs(t, verifyBuilderSignature(&tampered, signature, domain), ErrInvalidBuilderSignature)
+	require.ErrorIs(t, verifyBuilderSignature(message, phase0.BLSSignature{}, domain), ErrInvalidBuilderSignature)
+
+	require.Error(t, verifyBuilderSignature(nil, signature, domain))
+}
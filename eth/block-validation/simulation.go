@@ -0,0 +1,101 @@
+package blockvalidation
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// SimulationResult is the breakdown of a V2 validation reported by SimulateBuilderSubmissionV2.
+type SimulationResult struct {
+	Valid bool `json:"valid"`
+	// Value claimed by the builder in the bid.
+	ClaimedProfit *big.Int `json:"claimed_profit"`
+	// Proposer payment verified against the claimed value, nil unless the block is valid.
+	ComputedProfit *big.Int `json:"computed_profit"`
+	// Gas used by the executed transactions.
+	GasUsed uint64 `json:"gas_used"`
+	// Balance change of the fee recipient over the executed transactions, nil if the block was not
+	// executed.
+	FeeRecipientBalanceDelta *big.Int `json:"fee_recipient_balance_delta"`
+	FailureReason            string   `json:"failure_reason,omitempty"`
+	// Gas used by each executed transaction, in block order.
+	TxGasUsed []uint64 `json:"tx_gas_used"`
+}
+
+// SimulateBuilderSubmissionV2 validates the submission like ValidateBuilderSubmissionV2 and reports
+// how the block executed, whether it is valid or not. The state changes are never committed.
+func (api *BlockValidationAPI) SimulateBuilderSubmissionV2(ctx context.Context, params *BuilderBlockValidationRequestV2) *SimulationResult {
+	if params == nil {
+		return &SimulationResult{FailureReason: "nil request"}
+	}
+	if params.Message == nil {
+		return &SimulationResult{FailureReason: "nil bid message"}
+	}
+	simulation := &simulationTracer{feeRecipient: common.BytesToAddress(params.Message.ProposerFeeRecipient[:])}
+	_, result, err := api.validateBuilderSubmissionV2(ctx, params, simulation)
+
+	response := &SimulationResult{
+		Valid:         err == nil,
+		ClaimedProfit: params.Message.Value.ToBig(),
+		TxGasUsed:     simulation.gasUsed,
+	}
+	if err != nil {
+		response.FailureReason = err.Error()
+	}
+	for _, gas := range simulation.gasUsed {
+		response.GasUsed += gas
+	}
+	switch {
+	case result != nil:
+		response.ComputedProfit = result.Profit
+		response.FeeRecipientBalanceDelta = result.FeeRecipientBalanceDelta
+	case simulation.balance != nil:
+		if parent := api.chain.GetHeaderByHash(common.Hash(params.Message.ParentHash)); parent != nil {
+			if statedb, err := api.chain.StateAt(parent.Root); err == nil {
+				response.FeeRecipientBalanceDelta = new(big.Int).Sub(simulation.balance, statedb.GetBalance(simulation.feeRecipient))
+			}
+		}
+	}
+	return response
+}
+
+// simulationTracer records the gas used by every transaction of a replay and the balance of the
+// fee recipient after the last one.
+type simulationTracer struct {
+	feeRecipient common.Address
+	env          *vm.EVM
+	gasLimit     uint64
+	gasUsed      []uint64
+	balance      *big.Int
+}
+
+func (t *simulationTracer) CaptureTxStart(gasLimit uint64) {
+	t.gasLimit = gasLimit
+}
+
+func (t *simulationTracer) CaptureTxEnd(restGas uint64) {
+	t.gasUsed = append(t.gasUsed, t.gasLimit-restGas)
+	if t.env != nil {
+		t.balance = new(big.Int).Set(t.env.StateDB.GetBalance(t.feeRecipient))
+	}
+}
+
+func (t *simulationTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+}
+
+func (t *simulationTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *simulationTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+}
+
+func (t *simulationTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (t *simulationTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (t *simulationTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
@@ -0,0 +1,58 @@
+package blockvalidation
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateBuilderSubmissionV2(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	signer := types.LatestSigner(bc.Config())
+	nonce := statedb.GetNonce(testAddr)
+	tx1, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), signer, testKey)
+	tx2, _ := types.SignTx(types.NewTransaction(nonce+1, common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(3*baseFee.Int64()), nil), signer, testKey)
+	fees := big.NewInt(21000*baseFee.Int64() + 21000*2*baseFee.Int64())
+
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, ValidationCacheSize: 10})
+
+	valid := api.SimulateBuilderSubmissionV2(context.Background(), buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx1, tx2}, nil, fees))
+	require.True(t, valid.Valid, valid.FailureReason)
+	require.Equal(t, fees, valid.ClaimedProfit)
+	require.Equal(t, fees, valid.ComputedProfit)
+	require.Equal(t, fees, valid.FeeRecipientBalanceDelta)
+	require.Equal(t, uint64(42000), valid.GasUsed)
+	require.Equal(t, []uint64{21000, 21000}, valid.TxGasUsed)
+
+	// The breakdown is reported for blocks failing the payment check as well.
+	claimed := new(big.Int).Add(fees, common.Big1)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx1, tx2}, nil, claimed)
+	require.Error(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	invalid := api.SimulateBuilderSubmissionV2(context.Background(), req)
+	require.False(t, invalid.Valid)
+	require.NotEmpty(t, invalid.FailureReason)
+	require.Equal(t, claimed, invalid.ClaimedProfit)
+	require.Nil(t, invalid.ComputedProfit)
+	require.Equal(t, fees, invalid.FeeRecipientBalanceDelta)
+	require.Equal(t, []uint64{21000, 21000}, invalid.TxGasUsed)
+
+	require.Equal(t, "nil request", api.SimulateBuilderSubmissionV2(context.Background(), nil).FailureReason)
+
+	// Nothing was committed.
+	require.Equal(t, lastBlock.Hash(), bc.CurrentBlock().Hash())
+}
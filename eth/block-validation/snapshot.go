@@ -0,0 +1,66 @@
+package blockvalidation
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/clique"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// NewBlockValidationAPIFromSnapshot creates a block validation api backed by a read-only copy of
+// a chain database (e.g. a downloaded snapshot at block N) rather than a running eth service.
+// Only the chain is available in this mode, validation runs against the snapshot head state.
+// The database is kept open for the lifetime of the process.
+func NewBlockValidationAPIFromSnapshot(snapshotDir string, chainConfig *params.ChainConfig, useBalanceDiffProfit bool) (*BlockValidationAPI, error) {
+	db, err := rawdb.Open(rawdb.OpenOptions{
+		Directory:         snapshotDir,
+		AncientsDirectory: filepath.Join(snapshotDir, "ancient"),
+		Namespace:         "blockvalidation/snapshot/",
+		Cache:             512,
+		Handles:           256,
+		ReadOnly:          true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't open snapshot database: %w", err)
+	}
+
+	// Pre-merge seals are never verified, submissions are always proof-of-stake blocks.
+	var engine consensus.Engine
+	if chainConfig.Clique != nil {
+		engine = clique.New(chainConfig.Clique, db)
+	} else {
+		engine = ethash.NewFaker()
+	}
+
+	// The state snapshot is disabled as generating it would write to the database.
+	cacheConfig := &core.CacheConfig{
+		TrieCleanLimit: 256,
+		TrieDirtyLimit: 256,
+		TrieTimeLimit:  5 * time.Minute,
+	}
+	chain, err := core.NewBlockChain(db, cacheConfig, nil, nil, beacon.New(engine), vm.Config{}, nil, nil)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("can't load chain from snapshot: %w", err)
+	}
+	if chain.Config().ChainID.Cmp(chainConfig.ChainID) != 0 {
+		db.Close()
+		return nil, fmt.Errorf("snapshot chain id %d does not match configured chain id %d", chain.Config().ChainID, chainConfig.ChainID)
+	}
+
+	api, err := NewBlockValidationAPI(nil, nil, useBalanceDiffProfit, nil)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	api.chain = chain
+	return api, nil
+}
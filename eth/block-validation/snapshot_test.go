@@ -0,0 +1,69 @@
+package blockvalidation
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBlockValidationAPIFromSnapshot(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+
+	dir := t.TempDir()
+	db, err := rawdb.Open(rawdb.OpenOptions{
+		Directory:         dir,
+		AncientsDirectory: filepath.Join(dir, "ancient"),
+		Cache:             16,
+		Handles:           16,
+	})
+	require.NoError(t, err)
+	genesis.MustCommit(db)
+	chain, err := core.NewBlockChain(db, nil, genesis, nil, beacon.New(ethash.NewFaker()), vm.Config{}, nil, nil)
+	require.NoError(t, err)
+	_, err = chain.InsertChain(preMergeBlocks)
+	require.NoError(t, err)
+	chain.Stop()
+	require.NoError(t, db.Close())
+
+	_, err = NewBlockValidationAPIFromSnapshot(dir, &params.ChainConfig{ChainID: big.NewInt(5)}, true)
+	require.ErrorContains(t, err, "does not match configured chain id")
+
+	api, err := NewBlockValidationAPIFromSnapshot(dir, genesis.Config, true)
+	require.NoError(t, err)
+	require.Equal(t, lastBlock.Hash(), api.chain.CurrentBlock().Hash())
+
+	baseFee := misc.CalcBaseFee(genesis.Config, lastBlock.Header())
+	signer := types.LatestSigner(genesis.Config)
+	tx, _ := types.SignTx(types.NewTransaction(uint64(len(preMergeBlocks)), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), signer, testKey)
+
+	execData, err := buildBlock(buildBlockArgs{
+		parentHash:    lastBlock.Hash(),
+		parentRoot:    lastBlock.Root(),
+		feeRecipient:  testValidatorAddr,
+		txs:           types.Transactions{tx},
+		number:        lastBlock.NumberU64() + 1,
+		gasLimit:      lastBlock.GasLimit(),
+		timestamp:     lastBlock.Time() + 5,
+		baseFeePerGas: baseFee,
+	}, api.chain)
+	require.NoError(t, err)
+
+	req, err := executableDataToBlockValidationRequest(execData, testValidatorAddr, big.NewInt(21000*baseFee.Int64()), ComputeWithdrawalsRoot(nil))
+	require.NoError(t, err)
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+}
@@ -0,0 +1,32 @@
+package blockvalidation
+
+import (
+	"context"
+	"fmt"
+
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ValidateBuilderSubmissionV2SSZ validates a capella submission given as the SSZ encoding of the
+// submit block request, saving relays the conversion to JSON. The registered gas limit and the
+// withdrawals root are not part of the encoding and are passed separately. Requires EnableSSZ.
+func (api *BlockValidationAPI) ValidateBuilderSubmissionV2SSZ(ctx context.Context, payload hexutil.Bytes, registeredGasLimit hexutil.Uint64, withdrawalsRoot common.Hash) error {
+	if !api.cfg.EnableSSZ {
+		return ErrSSZDisabled
+	}
+	if err := checkPayloadSize(payload); err != nil {
+		return err
+	}
+	request := new(capellaapi.SubmitBlockRequest)
+	if err := request.UnmarshalSSZ(payload); err != nil {
+		return fmt.Errorf("invalid SSZ submission: %w", err)
+	}
+	_, _, err := api.validateBuilderSubmissionV2(ctx, &BuilderBlockValidationRequestV2{
+		SubmitBlockRequest: *request,
+		RegisteredGasLimit: uint64(registeredGasLimit),
+		WithdrawalsRoot:    withdrawalsRoot,
+	}, nil)
+	return err
+}
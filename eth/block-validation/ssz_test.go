@@ -0,0 +1,48 @@
+package blockvalidation
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBuilderSubmissionV2SSZ(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	withdrawals := types.Withdrawals{{Index: 0, Validator: 1, Address: common.Address{0x17}, Amount: 100}}
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, withdrawals, big.NewInt(21000*baseFee.Int64()))
+	payload, err := req.SubmitBlockRequest.MarshalSSZ()
+	require.NoError(t, err)
+	gasLimit := hexutil.Uint64(req.RegisteredGasLimit)
+
+	api, err := NewBlockValidationAPI(ethservice, nil, true, nil)
+	require.NoError(t, err)
+	err = api.ValidateBuilderSubmissionV2SSZ(context.Background(), payload, gasLimit, req.WithdrawalsRoot)
+	require.ErrorIs(t, err, ErrSSZDisabled)
+
+	api = newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true, EnableSSZ: true})
+	require.NoError(t, api.ValidateBuilderSubmissionV2SSZ(context.Background(), payload, gasLimit, req.WithdrawalsRoot))
+
+	err = api.ValidateBuilderSubmissionV2SSZ(context.Background(), payload, gasLimit, common.Hash{0x01})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, CodeWithdrawalsRootMismatch, validationErr.Code)
+
+	require.ErrorContains(t, api.ValidateBuilderSubmissionV2SSZ(context.Background(), payload[:len(payload)-1], gasLimit, req.WithdrawalsRoot), "invalid SSZ submission")
+}
@@ -0,0 +1,87 @@
+package blockvalidation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultThrottleBaseDelay = 50 * time.Millisecond
+	defaultThrottleMaxDelay  = 4 * time.Second
+
+	// throttledSlotsRetained is how many slots behind the latest one submission counts are kept for.
+	throttledSlotsRetained = 32
+)
+
+// SlotThrottler delays submissions once a slot has received more than the allowed number of
+// them. Each submission beyond the limit waits twice as long as the previous one, up to a
+// maximum delay, so builders are slowed down rather than rejected.
+type SlotThrottler struct {
+	maxPerSlot int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+
+	mu      sync.Mutex
+	counts  map[uint64]int
+	highest uint64
+}
+
+// NewSlotThrottler creates a throttler allowing maxPerSlot undelayed submissions per slot.
+func NewSlotThrottler(maxPerSlot int, baseDelay, maxDelay time.Duration) *SlotThrottler {
+	return &SlotThrottler{
+		maxPerSlot: maxPerSlot,
+		baseDelay:  baseDelay,
+		maxDelay:   maxDelay,
+		counts:     make(map[uint64]int),
+	}
+}
+
+// reserve counts a submission for the slot and returns how long it has to wait. Callers bound the
+// slot, a far future one would stop old slots from being forgotten.
+func (t *SlotThrottler) reserve(slot uint64) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if slot > t.highest {
+		t.highest = slot
+		for s := range t.counts {
+			if slot-s > throttledSlotsRetained {
+				delete(t.counts, s)
+			}
+		}
+	}
+	t.counts[slot]++
+
+	excess := t.counts[slot] - t.maxPerSlot
+	if excess <= 0 {
+		return 0
+	}
+	delay := t.baseDelay
+	for i := 1; i < excess && delay < t.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > t.maxDelay {
+		delay = t.maxDelay
+	}
+	return delay
+}
+
+// Wait blocks until the submission for the slot may proceed, or the context is done.
+func (t *SlotThrottler) Wait(ctx context.Context, slot uint64) error {
+	return sleepContext(ctx, t.reserve(slot))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
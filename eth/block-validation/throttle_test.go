@@ -0,0 +1,60 @@
+package blockvalidation
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlotThrottler(t *testing.T) {
+	throttler := NewSlotThrottler(2, 10*time.Millisecond, 35*time.Millisecond)
+
+	var delays []time.Duration
+	for i := 0; i < 6; i++ {
+		delays = append(delays, throttler.reserve(100))
+	}
+	require.Equal(t, []time.Duration{0, 0, 10 * time.Millisecond, 20 * time.Millisecond, 35 * time.Millisecond, 35 * time.Millisecond}, delays)
+
+	// Other slots are counted separately.
+	require.Zero(t, throttler.reserve(101))
+
+	// Old slots are forgotten once far enough behind the latest one.
+	throttler.reserve(100 + throttledSlotsRetained + 1)
+	require.NotContains(t, throttler.counts, uint64(100))
+	require.Contains(t, throttler.counts, uint64(101))
+
+	// The distance to the latest slot does not overflow.
+	throttler.reserve(math.MaxUint64)
+	require.Len(t, throttler.counts, 1)
+}
+
+func TestValidateBuilderSubmissionV2_ThrottleFutureSlot(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	defer n.Close()
+
+	head := ethservice.BlockChain().CurrentHeader()
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{BeaconGenesisTime: head.Time - 24, MaxSubmissionsPerSlot: 1})
+
+	// Submissions for slots beyond the chain head are counted for the slot of the head.
+	req := &BuilderBlockValidationRequestV2{SubmitBlockRequest: capellaapi.SubmitBlockRequest{Message: &apiv1.BidTrace{Slot: math.MaxUint64}}}
+	require.Error(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.Equal(t, map[uint64]int{3: 1}, api.throttler.counts)
+	require.Equal(t, uint64(3), api.throttler.highest)
+}
+
+func TestSlotThrottlerWait(t *testing.T) {
+	throttler := NewSlotThrottler(1, time.Second, time.Second)
+	require.NoError(t, throttler.Wait(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	require.ErrorIs(t, throttler.Wait(ctx, 1), context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+}
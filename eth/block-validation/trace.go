@@ -0,0 +1,67 @@
+package blockvalidation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// SubmissionTrace is the outcome of tracing a V2 submission with TraceBuilderSubmissionV2.
+type SubmissionTrace struct {
+	// Validation error of the submission, empty if it is valid.
+	ValidationError string `json:"validation_error,omitempty"`
+	// Traces of the transactions in the format of debug_traceBlock. For blocks with a transaction
+	// that could not be applied, only the transactions before it are traced.
+	Trace interface{} `json:"trace,omitempty"`
+}
+
+// TraceBuilderSubmissionV2 validates the submission like SimulateBuilderSubmissionV2 and traces its
+// transactions with the debug_traceBlock tracers on top of the parent state, without committing
+// any state. Submissions that can not be converted to a block are not traced. Tracing relies on the
+// eth service and is not available on APIs backed by a snapshot.
+func (api *BlockValidationAPI) TraceBuilderSubmissionV2(ctx context.Context, params *BuilderBlockValidationRequestV2, config *tracers.TraceConfig) (*SubmissionTrace, error) {
+	if api.eth == nil {
+		return nil, ErrNoEthService
+	}
+	if params == nil {
+		return &SubmissionTrace{ValidationError: "nil request"}, nil
+	}
+	if params.Message == nil {
+		return &SubmissionTrace{ValidationError: "nil bid message"}, nil
+	}
+	simulation := &simulationTracer{feeRecipient: common.BytesToAddress(params.Message.ProposerFeeRecipient[:])}
+	block, _, validationErr := api.validateBuilderSubmissionV2(ctx, params, simulation)
+
+	response := new(SubmissionTrace)
+	if validationErr != nil {
+		response.ValidationError = validationErr.Error()
+	}
+	if block == nil {
+		return response, nil
+	}
+	trace, err := api.traceBlock(ctx, block, config)
+	if err != nil && validationErr != nil {
+		// Trace the transactions before the first one that could not be applied.
+		if failed, findErr := api.findFailedTransaction(ctx, block, false); findErr == nil && failed != nil {
+			prefix := block.WithBody(block.Transactions()[:failed.index], block.Uncles()).WithWithdrawals(block.Withdrawals())
+			trace, err = api.traceBlock(ctx, prefix, config)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not trace block: %w", err)
+	}
+	response.Trace = trace
+	return response, nil
+}
+
+func (api *BlockValidationAPI) traceBlock(ctx context.Context, block *types.Block, config *tracers.TraceConfig) (interface{}, error) {
+	blob, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return nil, err
+	}
+	return tracers.NewAPI(api.eth.APIBackend).TraceBlock(ctx, blob, config)
+}
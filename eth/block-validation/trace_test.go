@@ -0,0 +1,80 @@
+package blockvalidation
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceBuilderSubmissionV2(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	nonce := statedb.GetNonce(testAddr)
+	signer := types.LatestSigner(bc.Config())
+	gasPrice := big.NewInt(2 * baseFee.Int64())
+	first, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x16}, big.NewInt(10), 21000, gasPrice, nil), signer, testKey)
+	second, _ := types.SignTx(types.NewTransaction(nonce+1, common.Address{0x16}, big.NewInt(10), 21000, gasPrice, nil), signer, testKey)
+	api := newBlockValidationAPI(ethservice, nil, BlockValidationConfig{UseBalanceDiffProfit: true})
+	callTracer := "callTracer"
+	config := &tracers.TraceConfig{Tracer: &callTracer}
+
+	traceLength := func(trace interface{}) int {
+		encoded, err := json.Marshal(trace)
+		require.NoError(t, err)
+		var txs []json.RawMessage
+		require.NoError(t, json.Unmarshal(encoded, &txs))
+		return len(txs)
+	}
+
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{first, second}, nil, big.NewInt(2*21000*baseFee.Int64()))
+	trace, err := api.TraceBuilderSubmissionV2(context.Background(), req, config)
+	require.NoError(t, err)
+	require.Empty(t, trace.ValidationError)
+	require.Equal(t, 2, traceLength(trace.Trace))
+
+	// The second transaction can not be applied without the first one, only the first is traced.
+	req = buildTestRequestV2(t, bc, lastBlock, types.Transactions{first}, nil, big.NewInt(21000*baseFee.Int64()))
+	tooHighNonce, _ := types.SignTx(types.NewTransaction(nonce+2, common.Address{0x16}, big.NewInt(10), 21000, gasPrice, nil), signer, testKey)
+	txData, err := tooHighNonce.MarshalBinary()
+	require.NoError(t, err)
+	req.ExecutionPayload.Transactions = append(req.ExecutionPayload.Transactions, txData)
+	updatePayloadHashV2(t, req)
+	trace, err = api.TraceBuilderSubmissionV2(context.Background(), req, config)
+	require.NoError(t, err)
+	require.Contains(t, trace.ValidationError, "nonce too high")
+	require.Equal(t, 1, traceLength(trace.Trace))
+
+	// Requests that are not blocks are not traced.
+	req.ExecutionPayload = nil
+	trace, err = api.TraceBuilderSubmissionV2(context.Background(), req, config)
+	require.NoError(t, err)
+	require.NotEmpty(t, trace.ValidationError)
+	require.Nil(t, trace.Trace)
+
+	trace, err = api.TraceBuilderSubmissionV2(context.Background(), nil, config)
+	require.NoError(t, err)
+	require.Equal(t, "nil request", trace.ValidationError)
+
+	// Without an eth service, as with a snapshot backed API, submissions are not traced.
+	api.eth = nil
+	_, err = api.TraceBuilderSubmissionV2(context.Background(), req, config)
+	require.ErrorIs(t, err, ErrNoEthService)
+	_, err = api.findFailedTransaction(context.Background(), types.NewBlockWithHeader(lastBlock.Header()), false)
+	require.ErrorIs(t, err, ErrNoEthService)
+}
@@ -0,0 +1,110 @@
+package blockvalidation
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+type storageKey struct {
+	address common.Address
+	slot    common.Hash
+}
+
+// trieDepthTracer measures the depth of the state trie paths of the accounts and storage slots
+// accessed during the replay of a block in the parent state, and aborts the execution at the
+// first path deeper than max.
+type trieDepthTracer struct {
+	max      int
+	statedb  *state.StateDB
+	env      *vm.EVM
+	accounts map[common.Address]struct{}
+	slots    map[storageKey]struct{}
+	err      error
+}
+
+func newTrieDepthTracer(max int, statedb *state.StateDB) *trieDepthTracer {
+	return &trieDepthTracer{
+		max:      max,
+		statedb:  statedb,
+		accounts: make(map[common.Address]struct{}),
+		slots:    make(map[storageKey]struct{}),
+	}
+}
+
+func (t *trieDepthTracer) abort(err error) {
+	t.err = err
+	if t.env != nil {
+		t.env.Cancel()
+	}
+}
+
+func (t *trieDepthTracer) account(address common.Address) {
+	if t.err != nil {
+		return
+	}
+	if _, ok := t.accounts[address]; ok {
+		return
+	}
+	t.accounts[address] = struct{}{}
+	proof, err := t.statedb.GetProof(address)
+	if err != nil {
+		t.abort(err)
+	} else if len(proof) > t.max {
+		t.abort(fmt.Errorf("%w: account %s at depth %d, max %d", ErrExcessiveTrieDepth, address, len(proof), t.max))
+	}
+}
+
+func (t *trieDepthTracer) storage(address common.Address, slot common.Hash) {
+	if t.err != nil {
+		return
+	}
+	key := storageKey{address, slot}
+	if _, ok := t.slots[key]; ok {
+		return
+	}
+	t.slots[key] = struct{}{}
+	proof, err := t.statedb.GetStorageProof(address, slot)
+	if err != nil {
+		t.abort(err)
+	} else if len(proof) > t.max {
+		t.abort(fmt.Errorf("%w: slot %s of %s at depth %d, max %d", ErrExcessiveTrieDepth, slot, address, len(proof), t.max))
+	}
+}
+
+func (t *trieDepthTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (t *trieDepthTracer) CaptureTxEnd(restGas uint64) {}
+
+func (t *trieDepthTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	t.env = env
+	t.account(from)
+	t.account(to)
+}
+
+func (t *trieDepthTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func (t *trieDepthTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	t.account(to)
+}
+
+func (t *trieDepthTracer) CaptureExit(output []byte, gasUsed uint64, err error) {}
+
+func (t *trieDepthTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+	stack := scope.Stack
+	if len(stack.Data()) == 0 {
+		return
+	}
+	switch op {
+	case vm.SLOAD, vm.SSTORE:
+		t.storage(scope.Contract.Address(), common.Hash(stack.Back(0).Bytes32()))
+	case vm.BALANCE, vm.EXTCODESIZE, vm.EXTCODECOPY, vm.EXTCODEHASH, vm.SELFDESTRUCT:
+		t.account(common.Address(stack.Back(0).Bytes20()))
+	}
+}
+
+func (t *trieDepthTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+}
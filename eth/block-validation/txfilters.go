@@ -0,0 +1,41 @@
+package blockvalidation
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TransactionFilter inspects a transaction of a V2 block before the block is executed, returning
+// an error rejects the block.
+type TransactionFilter = func(*types.Transaction) error
+
+// BlockedRecipientFilter returns a filter rejecting transactions sent to one of the addresses.
+func BlockedRecipientFilter(addresses ...common.Address) TransactionFilter {
+	blocked := make(map[common.Address]struct{}, len(addresses))
+	for _, address := range addresses {
+		blocked[address] = struct{}{}
+	}
+	return func(tx *types.Transaction) error {
+		if to := tx.To(); to != nil {
+			if _, ok := blocked[*to]; ok {
+				return ErrBlockedRecipient{TxHash: tx.Hash(), Address: *to}
+			}
+		}
+		return nil
+	}
+}
+
+// applyTxFilters runs every filter on every transaction and returns the first error.
+func applyTxFilters(txs types.Transactions, filters []TransactionFilter) error {
+	if len(filters) == 0 {
+		return nil
+	}
+	for _, tx := range txs {
+		for _, filter := range filters {
+			if err := filter(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
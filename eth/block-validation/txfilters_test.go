@@ -0,0 +1,71 @@
+package blockvalidation
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockedRecipientFilter(t *testing.T) {
+	blocked := common.Address{0xbb}
+	filter := BlockedRecipientFilter(blocked)
+
+	toBlocked := types.NewTransaction(0, blocked, common.Big0, 21000, common.Big1, nil)
+	require.Equal(t, ErrBlockedRecipient{TxHash: toBlocked.Hash(), Address: blocked}, filter(toBlocked))
+	require.NoError(t, filter(types.NewTransaction(0, common.Address{0xaa}, common.Big0, 21000, common.Big1, nil)))
+	require.NoError(t, filter(types.NewContractCreation(0, common.Big0, 100000, common.Big1, nil)))
+}
+
+func TestApplyTxFilters(t *testing.T) {
+	txs := types.Transactions{
+		types.NewTransaction(0, common.Address{0xaa}, common.Big0, 21000, common.Big1, nil),
+		types.NewTransaction(1, common.Address{0xbb}, common.Big0, 21000, common.Big1, nil),
+	}
+	errFirst, errSecond := errors.New("first"), errors.New("second")
+	var calls int
+	counting := func(tx *types.Transaction) error {
+		calls++
+		return nil
+	}
+
+	require.NoError(t, applyTxFilters(txs, nil))
+	require.NoError(t, applyTxFilters(txs, []TransactionFilter{counting, counting}))
+	require.Equal(t, 4, calls)
+
+	// The first error is returned, all filters run on a transaction before the next one.
+	failFirst := func(tx *types.Transaction) error { return errFirst }
+	failSecond := func(tx *types.Transaction) error { return errSecond }
+	require.ErrorIs(t, applyTxFilters(txs, []TransactionFilter{failFirst, failSecond}), errFirst)
+	require.ErrorIs(t, applyTxFilters(txs, []TransactionFilter{BlockedRecipientFilter(common.Address{0xbb}), failSecond}), errSecond)
+	require.IsType(t, ErrBlockedRecipient{}, applyTxFilters(txs, []TransactionFilter{counting, BlockedRecipientFilter(common.Address{0xbb})}))
+}
+
+func TestValidateBuilderSubmissionV2_TxFilters(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	cfg := BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true, TxFilters: []TransactionFilter{BlockedRecipientFilter(common.Address{0x17})}}
+	api := newBlockValidationAPI(ethservice, nil, cfg)
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	cfg.TxFilters = append(cfg.TxFilters, BlockedRecipientFilter(common.Address{0x16}))
+	api = newBlockValidationAPI(ethservice, nil, cfg)
+	require.Equal(t, ErrBlockedRecipient{TxHash: tx.Hash(), Address: common.Address{0x16}}, api.ValidateBuilderSubmissionV2(context.Background(), req))
+}
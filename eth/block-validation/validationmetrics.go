@@ -0,0 +1,57 @@
+package blockvalidation
+
+import (
+	"math/big"
+	"time"
+
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BlockValidationMetrics are the Prometheus metrics of the V1 and V2 validations.
+type BlockValidationMetrics struct {
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	claimedProfit prometheus.Histogram
+}
+
+// NewBlockValidationMetrics creates the validation metrics and registers them with the registerer.
+func NewBlockValidationMetrics(registerer prometheus.Registerer) (*BlockValidationMetrics, error) {
+	m := &BlockValidationMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "blockvalidation_requests_total",
+			Help: "Validation requests by payload version and result, the error code for failed validations.",
+		}, []string{"version", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "blockvalidation_duration_seconds",
+			Help:    "Duration of the validations by payload version.",
+			Buckets: prometheus.ExponentialBuckets(0.005, 2, 12),
+		}, []string{"version"}),
+		claimedProfit: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "blockvalidation_claimed_profit_gwei",
+			Help:    "Proposer payment claimed by the submissions in gwei.",
+			Buckets: prometheus.ExponentialBuckets(1e5, 4, 10),
+		}),
+	}
+	for _, collector := range []prometheus.Collector{m.requests, m.duration, m.claimedProfit} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// observe records a validation of the given payload version.
+func (m *BlockValidationMetrics) observe(version string, duration time.Duration, msg *apiv1.BidTrace, err error) {
+	result := "success"
+	if err != nil {
+		result = auditErrorCode(err)
+	}
+	m.requests.WithLabelValues(version, result).Inc()
+	m.duration.WithLabelValues(version).Observe(duration.Seconds())
+	if msg != nil && msg.Value != nil {
+		gwei, _ := new(big.Float).Quo(new(big.Float).SetInt(msg.Value.ToBig()), big.NewFloat(params.GWei)).Float64()
+		m.claimedProfit.Observe(gwei)
+	}
+}
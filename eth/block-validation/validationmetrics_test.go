@@ -0,0 +1,67 @@
+package blockvalidation
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	bellatrixapi "github.com/attestantio/go-builder-client/api/bellatrix"
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockValidationMetrics(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	time := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &time
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	tx, _ := types.SignTx(types.NewTransaction(statedb.GetNonce(testAddr), common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), types.LatestSigner(bc.Config()), testKey)
+	req := buildTestRequestV2(t, bc, lastBlock, types.Transactions{tx}, nil, big.NewInt(21000*baseFee.Int64()))
+
+	registry := prometheus.NewRegistry()
+	api, err := NewBlockValidationAPI(ethservice, nil, true, registry)
+	require.NoError(t, err)
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	req.ExecutionPayload = nil
+	require.Error(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+
+	require.Equal(t, 2.0, testutil.ToFloat64(api.metrics.requests.WithLabelValues("v2", "success")))
+	require.Equal(t, 1.0, testutil.ToFloat64(api.metrics.requests.WithLabelValues("v2", "ErrNilPayload")))
+	require.Equal(t, 0.0, testutil.ToFloat64(api.metrics.requests.WithLabelValues("v1", "success")))
+	require.Equal(t, 1, testutil.CollectAndCount(api.metrics.duration))
+	require.Equal(t, 1, testutil.CollectAndCount(api.metrics.claimedProfit))
+
+	// The metrics can only be registered once.
+	_, err = NewBlockValidationMetrics(registry)
+	require.Error(t, err)
+	_, err = NewBlockValidationAPI(ethservice, nil, true, registry)
+	require.Error(t, err)
+}
+
+func TestBlockValidationMetricsEarlyRejections(t *testing.T) {
+	api := newBlockValidationAPI(nil, nil, BlockValidationConfig{})
+	metrics, err := NewBlockValidationMetrics(prometheus.NewRegistry())
+	require.NoError(t, err)
+	api.metrics, api.headBreaker = metrics, &headBreaker{open: true}
+
+	// Submissions rejected before validation starts are observed as well.
+	msg := &apiv1.BidTrace{Slot: 42}
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV1(context.Background(), &BuilderBlockValidationRequest{SubmitBlockRequest: bellatrixapi.SubmitBlockRequest{Message: msg}}), ErrCircuitOpen)
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), &BuilderBlockValidationRequestV2{SubmitBlockRequest: capellaapi.SubmitBlockRequest{Message: msg}}), ErrCircuitOpen)
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.requests.WithLabelValues("v1", "ErrCircuitOpen")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.requests.WithLabelValues("v2", "ErrCircuitOpen")))
+}
@@ -0,0 +1,75 @@
+package blockvalidation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// VMConfigOverrides are the vm.Config settings a V2 request may change for its replay, only
+// accepted if AllowVMConfigOverrides is set. Other settings can not be overridden.
+type VMConfigOverrides struct {
+	// Logs the calls and faults of the replay at debug level.
+	EnableDebug bool `json:"enable_debug"`
+	// Sets vm.Config.NoBaseFee, so that transactions with zero fees do not have to pay the base fee.
+	DisableBaseFeeCheck bool `json:"disable_base_fee_check"`
+}
+
+// UnmarshalJSON rejects unknown overrides.
+func (o *VMConfigOverrides) UnmarshalJSON(data []byte) error {
+	type overrides VMConfigOverrides
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode((*overrides)(o)); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidVMConfigOverrides, err)
+	}
+	return nil
+}
+
+// vmConfig returns the configuration of the replay with the overrides applied.
+func (o *VMConfigOverrides) vmConfig() vm.Config {
+	var config vm.Config
+	if o == nil {
+		return config
+	}
+	config.NoBaseFee = o.DisableBaseFeeCheck
+	if o.EnableDebug {
+		addTracer(&config, debugLogTracer{})
+	}
+	return config
+}
+
+// debugLogTracer logs the calls and faults of the EVM.
+type debugLogTracer struct{}
+
+func (debugLogTracer) CaptureTxStart(gasLimit uint64) {}
+
+func (debugLogTracer) CaptureTxEnd(restGas uint64) {}
+
+func (debugLogTracer) CaptureStart(env *vm.EVM, from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) {
+	log.Debug("EVM call", "from", from, "to", to, "create", create, "input", len(input), "gas", gas, "value", value)
+}
+
+func (debugLogTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	log.Debug("EVM call complete", "output", len(output), "gasUsed", gasUsed, "err", err)
+}
+
+func (debugLogTracer) CaptureEnter(typ vm.OpCode, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	log.Debug("EVM inner call", "type", typ, "from", from, "to", to, "input", len(input), "gas", gas, "value", value)
+}
+
+func (debugLogTracer) CaptureExit(output []byte, gasUsed uint64, err error) {
+	log.Debug("EVM inner call complete", "output", len(output), "gasUsed", gasUsed, "err", err)
+}
+
+func (debugLogTracer) CaptureState(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, rData []byte, depth int, err error) {
+}
+
+func (debugLogTracer) CaptureFault(pc uint64, op vm.OpCode, gas, cost uint64, scope *vm.ScopeContext, depth int, err error) {
+	log.Debug("EVM fault", "pc", pc, "op", op, "gas", gas, "cost", cost, "depth", depth, "err", err)
+}
@@ -0,0 +1,98 @@
+package blockvalidation
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	capellaapi "github.com/attestantio/go-builder-client/api/capella"
+	apiv1 "github.com/attestantio/go-builder-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalVMConfigOverrides(t *testing.T) {
+	request := &capellaapi.SubmitBlockRequest{
+		Message:          &apiv1.BidTrace{Value: uint256.NewInt(0)},
+		ExecutionPayload: &capella.ExecutionPayload{Transactions: []bellatrix.Transaction{}, Withdrawals: []*capella.Withdrawal{}},
+	}
+
+	var req BuilderBlockValidationRequestV2
+	require.NoError(t, json.Unmarshal(fuzzSeedRequest(t, request, map[string]string{"registered_gas_limit": `"30000000"`}), &req))
+	require.Nil(t, req.VMConfigOverrides)
+
+	data := fuzzSeedRequest(t, request, map[string]string{"registered_gas_limit": `"30000000"`, "vm_config_overrides": `{"enable_debug":true,"disable_base_fee_check":false}`})
+	require.NoError(t, json.Unmarshal(data, &req))
+	require.Equal(t, &VMConfigOverrides{EnableDebug: true}, req.VMConfigOverrides)
+
+	// Settings outside the allowlist are rejected.
+	data = fuzzSeedRequest(t, request, map[string]string{"registered_gas_limit": `"30000000"`, "vm_config_overrides": `{"extra_eips":[3855]}`})
+	require.ErrorIs(t, json.Unmarshal(data, &req), ErrInvalidVMConfigOverrides)
+}
+
+func TestVMConfigOverrides(t *testing.T) {
+	var overrides *VMConfigOverrides
+	require.Equal(t, vm.Config{}, overrides.vmConfig())
+
+	config := (&VMConfigOverrides{DisableBaseFeeCheck: true}).vmConfig()
+	require.True(t, config.NoBaseFee)
+	require.False(t, config.Debug)
+
+	config = (&VMConfigOverrides{EnableDebug: true}).vmConfig()
+	require.True(t, config.Debug)
+	require.Equal(t, debugLogTracer{}, config.Tracer)
+}
+
+func TestValidateBuilderSubmissionV2_VMConfigOverrides(t *testing.T) {
+	genesis, preMergeBlocks := generatePreMergeChain(20)
+	lastBlock := preMergeBlocks[len(preMergeBlocks)-1]
+	shanghaiTime := lastBlock.Time() + 5
+	genesis.Config.ShanghaiTime = &shanghaiTime
+	n, ethservice := startEthService(t, genesis, preMergeBlocks)
+	ethservice.Merger().ReachTTD()
+	defer n.Close()
+
+	bc := ethservice.BlockChain()
+	baseFee := misc.CalcBaseFee(bc.Config(), lastBlock.Header())
+	signer := types.LatestSigner(bc.Config())
+	statedb, _ := bc.StateAt(lastBlock.Root())
+	nonce := statedb.GetNonce(testAddr)
+	// A transaction without fees, only valid if the base fee is not charged.
+	free, _ := types.SignTx(types.NewTransaction(nonce, common.Address{0x16}, big.NewInt(10), 21000, common.Big0, nil), signer, testKey)
+	paying, _ := types.SignTx(types.NewTransaction(nonce+1, common.Address{0x16}, big.NewInt(10), 21000, big.NewInt(2*baseFee.Int64()), nil), signer, testKey)
+
+	execData, err := buildBlock(buildBlockArgs{
+		parentHash:    lastBlock.Hash(),
+		parentRoot:    lastBlock.Root(),
+		feeRecipient:  testValidatorAddr,
+		txs:           types.Transactions{free, paying},
+		number:        lastBlock.NumberU64() + 1,
+		gasLimit:      lastBlock.GasLimit(),
+		timestamp:     lastBlock.Time() + 5,
+		baseFeePerGas: baseFee,
+		vmConfig:      vm.Config{NoBaseFee: true},
+	}, bc)
+	require.NoError(t, err)
+	req, err := executableDataToBlockValidationRequest(execData, testValidatorAddr, big.NewInt(21000*baseFee.Int64()), ComputeWithdrawalsRoot(nil))
+	require.NoError(t, err)
+
+	cfg := BlockValidationConfig{UseBalanceDiffProfit: true, AllowIndirectPayment: true}
+	api := newBlockValidationAPI(ethservice, nil, cfg)
+	req.VMConfigOverrides = &VMConfigOverrides{EnableDebug: true, DisableBaseFeeCheck: true}
+	require.ErrorIs(t, api.ValidateBuilderSubmissionV2(context.Background(), req), ErrVMConfigOverridesNotAllowed)
+
+	// Resubmissions with different overrides are not answered with the earlier outcome.
+	cfg.AllowVMConfigOverrides = true
+	api = newBlockValidationAPI(ethservice, nil, cfg)
+	req.VMConfigOverrides = nil
+	require.IsType(t, ErrGasFeeCapBelowBaseFee{}, api.ValidateBuilderSubmissionV2(context.Background(), req))
+	req.VMConfigOverrides = &VMConfigOverrides{EnableDebug: true, DisableBaseFeeCheck: true}
+	require.NoError(t, api.ValidateBuilderSubmissionV2(context.Background(), req))
+}
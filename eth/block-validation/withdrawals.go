@@ -0,0 +1,47 @@
+package blockvalidation
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	ssz "github.com/ferranbt/fastssz"
+	"github.com/flashbots/go-boost-utils/bls"
+)
+
+// withdrawalsHashTreeRoot returns the SSZ hash tree root of the withdrawal list of a Capella
+// execution payload.
+func withdrawalsHashTreeRoot(withdrawals []*capella.Withdrawal) (phase0.Root, error) {
+	if len(withdrawals) > MaxWithdrawalsPerBlock {
+		return phase0.Root{}, ssz.ErrIncorrectListSize
+	}
+	hh := ssz.NewHasher()
+	indx := hh.Index()
+	for _, w := range withdrawals {
+		if err := w.HashTreeRootWith(hh); err != nil {
+			return phase0.Root{}, err
+		}
+	}
+	hh.MerkleizeWithMixin(indx, uint64(len(withdrawals)), MaxWithdrawalsPerBlock)
+	return hh.HashRoot()
+}
+
+// verifyWithdrawalListSignature verifies the BLS signature of the relay over the hash tree root
+// of the withdrawal list, with which a relay attests that it produced the list itself.
+func verifyWithdrawalListSignature(withdrawals []*capella.Withdrawal, signature []byte, relayPubkey phase0.BLSPubKey) error {
+	if relayPubkey == (phase0.BLSPubKey{}) {
+		return newValidationError(CodeNoRelayPubkey, "withdrawal list signature provided but no relay pubkey configured")
+	}
+	root, err := withdrawalsHashTreeRoot(withdrawals)
+	if err != nil {
+		return err
+	}
+	ok, err := bls.VerifySignatureBytes(root[:], signature, relayPubkey[:])
+	if err != nil {
+		return wrapValidationError(CodeInvalidSignature, fmt.Errorf("%w: %v", ErrInvalidWithdrawalListSignature, err))
+	}
+	if !ok {
+		return wrapValidationError(CodeInvalidSignature, ErrInvalidWithdrawalListSignature)
+	}
+	return nil
+}
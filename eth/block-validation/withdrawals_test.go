@@ -0,0 +1,39 @@
+package blockvalidation
+
+import (
+	"testing"
+
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/flashbots/go-boost-utils/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyWithdrawalListSignature(t *testing.T) {
+	withdrawals := []*capella.Withdrawal{
+		{Index: 1, ValidatorIndex: 2, Address: bellatrix.ExecutionAddress{0x03}, Amount: 4},
+		{Index: 2, ValidatorIndex: 3, Address: bellatrix.ExecutionAddress{0x04}, Amount: 5},
+	}
+	sk, pk, err := bls.GenerateNewKeypair()
+	require.NoError(t, err)
+	var relayPubkey phase0.BLSPubKey
+	copy(relayPubkey[:], bls.PublicKeyToBytes(pk))
+
+	root, err := withdrawalsHashTreeRoot(withdrawals)
+	require.NoError(t, err)
+	signature := bls.SignatureToBytes(bls.Sign(sk, root[:]))
+	require.NoError(t, verifyWithdrawalListSignature(withdrawals, signature, relayPubkey))
+
+	// The signature does not cover a different withdrawal list.
+	require.ErrorIs(t, verifyWithdrawalListSignature(withdrawals[:1], signature, relayPubkey), ErrInvalidWithdrawalListSignature)
+	require.ErrorIs(t, verifyWithdrawalListSignature(withdrawals, []byte{0x01}, relayPubkey), ErrInvalidWithdrawalListSignature)
+
+	_, otherPk, err := bls.GenerateNewKeypair()
+	require.NoError(t, err)
+	var otherPubkey phase0.BLSPubKey
+	copy(otherPubkey[:], bls.PublicKeyToBytes(otherPk))
+	require.ErrorIs(t, verifyWithdrawalListSignature(withdrawals, signature, otherPubkey), ErrInvalidWithdrawalListSignature)
+
+	require.ErrorContains(t, verifyWithdrawalListSignature(withdrawals, signature, phase0.BLSPubKey{}), "no relay pubkey configured")
+}